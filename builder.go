@@ -4,25 +4,26 @@
 package sqlbuilder
 
 import (
-	"fmt"
+	"errors"
 	"log"
 	"os"
+	"strconv"
 	"strings"
 )
 
 var (
-	errLog     = log.New(os.Stderr, "", log.Ldate|log.Ltime|log.Lmicroseconds|log.Lshortfile)
-	isErrorLog = false
+	errLog = log.New(os.Stderr, "", log.Ldate|log.Ltime|log.Lmicroseconds|log.Lshortfile)
 )
 
 // NewSQLBuilder can create a sqlbuilder object
+// d is the driver type, it can be any name of RegisterDialect(), default is `mysql`
 func NewSQLBuilder(d ...string) (b *SQLBuilder) {
-	driverName := "mysql"
+	dialect, _ := getDialect("mysql")
 	if len(d) > 0 && checkDriveType(d[0]) {
-		driverName = d[0]
+		dialect, _ = getDialect(d[0])
 	}
 	b = &SQLBuilder{
-		driverType: driverName,
+		dialect: dialect,
 	}
 	b.ClearBuilder()
 
@@ -30,18 +31,7 @@ func NewSQLBuilder(d ...string) (b *SQLBuilder) {
 }
 
 func checkDriveType(d string) (b bool) {
-	switch d {
-	case "mysql":
-		fallthrough
-	case "mssql":
-		fallthrough
-	case "oracle":
-		fallthrough
-	case "postgresql":
-		fallthrough
-	case "SQLite":
-		b = true
-	}
+	_, b = getDialect(d)
 
 	return
 }
@@ -84,43 +74,74 @@ func EscapeStr(value string, isMysql ...bool) string {
 }
 
 // SwitchPanicToErrorLog is a internal function
+// it only switch the ErrorPolicy of this builder
 func (sb *SQLBuilder) SwitchPanicToErrorLog(b bool) {
-	isErrorLog = b
+	if b {
+		sb.errPolicy = ErrorLog
+	} else {
+		sb.errPolicy = ErrorPanic
+	}
 }
 
 // PanicOrErrorLog is a internal function
+// it panic, log or do nothing by the ErrorPolicy of builder
 func (sb *SQLBuilder) PanicOrErrorLog(s string) {
-	if isErrorLog {
-		errLog.Println(s)
-	} else {
-		panic(s)
-	}
+	sb.handleError(errors.New(s))
 }
 
 // SetDriverType is a internal function
 func (sb *SQLBuilder) SetDriverType(t string) {
-	if checkDriveType(t) {
-		sb.driverType = t
+	if d, ok := getDialect(t); ok {
+		sb.dialect = d
 	}
 }
 
+// SetDialect set a dialect that not registered
+func (sb *SQLBuilder) SetDialect(d Dialect) {
+	if d != nil {
+		sb.dialect = d
+	}
+}
+
+// GetDialect return the dialect of builder
+func (sb *SQLBuilder) GetDialect() Dialect {
+	return sb.dialect
+}
+
+// GetDriverType return the driver type name of builder
+func (sb *SQLBuilder) GetDriverType() string {
+	return sb.dialect.Name()
+}
+
 // ClearBuilder that reset the sqlbuilder
 func (sb *SQLBuilder) ClearBuilder() {
+	sb.ctes = make([]cte, 0)
+	sb.windows = make([]window, 0)
+	sb.compounds = make([]compound, 0)
 	sb.distinct = false
 	sb.buildedStr = ""
-	sb.selects = make([]string, 0)
-	sb.froms = make([]string, 0)
-	sb.joins = make([]string, 0)
-	sb.wheres = make([]string, 0)
-	sb.orders = make([]string, 0)
-	sb.groups = make([]string, 0)
-	sb.havings = ""
-	sb.limit = ""
-	sb.top = ""
+	sb.selects = make([]Expr, 0)
+	sb.froms = make([]Expr, 0)
+	sb.buildedArgs = make([]interface{}, 0)
+	sb.joins = make([]join, 0)
+	sb.wheres = make([]SubCond, 0)
+	sb.orders = make([]order, 0)
+	sb.groups = make([]Expr, 0)
+	sb.havings = make([]SubCond, 0)
+	sb.limit = 0
+	sb.offset = 0
+	sb.top = 0
+	sb.forUpdate = false
 	sb.into = ""
 	sb.fields = make([]string, 0)
 	sb.values = make([][]interface{}, 0)
+	sb.fromSelect = nil
 	sb.sets = make([]Set, 0)
+	sb.upsert = nil
+	sb.merge = nil
+	sb.returnings = make([]string, 0)
+	sb.errs = make([]error, 0)
+	sb.buildErrs = make([]error, 0)
 }
 
 // SetDbName set a default db name
@@ -153,27 +174,42 @@ func (sb *SQLBuilder) GetTbName() string {
 
 // IsMysql return the builder engine is for mysql
 func (sb *SQLBuilder) IsMysql() bool {
-	return sb.driverType == "mysql"
+	return sb.GetDriverType() == "mysql"
 }
 
 // IsMssql return the builder engine is for mssql
 func (sb *SQLBuilder) IsMssql() bool {
-	return sb.driverType == "mssql"
+	return sb.GetDriverType() == "mssql"
+}
+
+// IsMariadb return the builder engine is for mariadb
+func (sb *SQLBuilder) IsMariadb() bool {
+	return sb.GetDriverType() == "mariadb"
 }
 
 // IsOracle return the builder engine is for oracle
 func (sb *SQLBuilder) IsOracle() bool {
-	return sb.driverType == "oracle"
+	return sb.GetDriverType() == "oracle"
 }
 
 // IsPostgresql return the builder engine is for postgresql
 func (sb *SQLBuilder) IsPostgresql() bool {
-	return sb.driverType == "postgresql"
+	return sb.GetDriverType() == "postgresql"
 }
 
 // IsSQLite return the builder engine is for SQLite
 func (sb *SQLBuilder) IsSQLite() bool {
-	return sb.driverType == "SQLite"
+	return sb.GetDriverType() == "SQLite"
+}
+
+// IsQuoteIdentifiers return the builder quote the table and column names
+func (sb *SQLBuilder) IsQuoteIdentifiers() bool {
+	return sb.quoteIdent
+}
+
+// IsParameterized return the builder bind values as placeholder args
+func (sb *SQLBuilder) IsParameterized() bool {
+	return sb.parameterized
 }
 
 // IsDistinct is internal function
@@ -228,17 +264,27 @@ func (sb *SQLBuilder) IsHasGroups() bool {
 
 // IsHasHavings is internal function
 func (sb *SQLBuilder) IsHasHavings() bool {
-	return sb.havings != ""
+	return len(sb.havings) > 0
 }
 
 // IsHasLimit is internal function
 func (sb *SQLBuilder) IsHasLimit() bool {
-	return (sb.IsMysql() || sb.IsSQLite()) && sb.limit != ""
+	return sb.limit > 0
+}
+
+// IsHasOffset is internal function
+func (sb *SQLBuilder) IsHasOffset() bool {
+	return sb.offset > 0
 }
 
 // IsHasTop is internal function
 func (sb *SQLBuilder) IsHasTop() bool {
-	return sb.IsMssql() && sb.top != ""
+	return sb.top > 0
+}
+
+// IsForUpdate is internal function
+func (sb *SQLBuilder) IsForUpdate() bool {
+	return sb.forUpdate
 }
 
 // IsHasInto is internal function
@@ -276,228 +322,447 @@ func (sb *SQLBuilder) BuildedSQL() (sql string) {
 	return sb.buildedStr
 }
 
+// BuildedArgs return the args of placeholders in builded SQL string
+// it only has values when Parameterized(true)
+func (sb *SQLBuilder) BuildedArgs() (args []interface{}) {
+	return sb.buildedArgs
+}
+
+// BuildedSQLArgs return the builded SQL string with its args
+// that can pass to db.QueryContext() directly
+func (sb *SQLBuilder) BuildedSQLArgs() (sql string, args []interface{}) {
+	return sb.buildedStr, sb.buildedArgs
+}
+
+// build run the fn with a new build context
+// the builded SQL string and args only be set when without any error
+func (sb *SQLBuilder) build(fn func(ctx *buildCtx) string) (string, []interface{}, error) {
+	ctx := sb.newBuildCtx()
+	sql := ""
+	if len(sb.errs) == 0 {
+		sql = fn(ctx)
+	}
+	sb.buildErrs = ctx.errs
+	if err := sb.Err(); err != nil {
+		sb.buildedStr, sb.buildedArgs = "", make([]interface{}, 0)
+		return "", nil, err
+	}
+	sb.setBuilded(ctx, sql)
+
+	return sql, ctx.args, nil
+}
+
+// buildSQL is the build for the Build...SQL() chain functions
+func (sb *SQLBuilder) buildSQL(fn func(ctx *buildCtx) string) *SQLBuilder {
+	if _, _, err := sb.build(fn); err != nil {
+		sb.handleError(err)
+	}
+
+	return sb
+}
+
 // BuildDeleteSQL do build the `delete` SQL string
 func (sb *SQLBuilder) BuildDeleteSQL() *SQLBuilder {
+	return sb.buildSQL(sb.buildDelete)
+}
+
+// BuildDelete do build the `delete` SQL string, return with args and error
+func (sb *SQLBuilder) BuildDelete() (string, []interface{}, error) {
+	return sb.build(sb.buildDelete)
+}
+
+func (sb *SQLBuilder) buildDelete(ctx *buildCtx) string {
 	if !sb.CanBuildDelete() {
-		sb.PanicOrErrorLog("must be have only one from table or default TbName")
+		ctx.addError(ErrMissingTable, "must be have from table or default TbName")
+		return ""
 	}
-	sql := ""
-	if sb.IsHasOneFroms() {
-		sql = "DELETE FROM " + sb.froms[0]
-	} else if sb.IsHasTbName() {
-		sql = "DELETE FROM " + sb.tbName
+
+	sql, ok := sb.with(ctx, "DELETE")
+	if !ok {
+		return ""
 	}
 
-	if sb.IsHasWheres() {
-		sql += " WHERE " + strings.Join(sb.wheres, " ")
+	if sb.IsMultiTable() {
+		multi, ok := sb.multiDelete(ctx)
+		if !ok {
+			return ""
+		}
+		sql += multi
+	} else {
+		sql += "DELETE FROM " + sb.table(ctx) + sb.output(ctx, "DELETE")
+
+		if sb.IsHasWheres() {
+			sql += " WHERE " + ctx.conds(sb.wheres)
+		}
 	}
-	sb.buildedStr = sql
 
-	return sb
+	returning, ok := sb.returning(ctx, "DELETE", 0)
+	if !ok {
+		return ""
+	}
+
+	return sql + returning
 }
 
 // BuildSelectSQL do build the `select` SQL string
 func (sb *SQLBuilder) BuildSelectSQL() *SQLBuilder {
+	return sb.buildSQL(sb.buildSelect)
+}
+
+// BuildSelect do build the `select` SQL string, return with args and error
+func (sb *SQLBuilder) BuildSelect() (string, []interface{}, error) {
+	return sb.build(sb.buildSelect)
+}
+
+// buildSelect render the clauses in the standard order :
+// WITH, SELECT, FROM, JOIN, WHERE, GROUP BY, HAVING, WINDOW, compound, ORDER BY, pagination, locking
+func (sb *SQLBuilder) buildSelect(ctx *buildCtx) string {
+	if !sb.IsHasSelects() {
+		ctx.addError(ErrMissingFields, "Without selects")
+		return ""
+	}
 	if !sb.CanBuildSelect() {
-		sb.PanicOrErrorLog("Without selects or from table is not set")
+		ctx.addError(ErrMissingTable, "from table is not set")
+		return ""
+	}
+	if sb.IsHasTop() && (sb.IsHasLimit() || sb.IsHasOffset()) {
+		ctx.addError(ErrInvalidArgument, "top can not be set with limit or offset")
+		return ""
+	}
+
+	style := ctx.dialect.PageStyle()
+	canTop := style == PageTop || isTSQL(ctx.dialect)
+	top := sb.top
+	// the limit without offset is `TOP n` for mssql, it work for all versions
+	if canTop && sb.IsHasLimit() && !sb.IsHasOffset() {
+		top = sb.limit
+	}
+	// the `TOP n` of compound query is wrapped, else it only for the first select
+	wrapTop := false
+	if sb.IsHasCompounds() && top > 0 {
+		if wrapTop = sb.IsHasTop() || style == PageTop; !wrapTop {
+			top = 0
+		}
+	}
+
+	with, ok := sb.with(ctx, "SELECT")
+	if !ok {
+		return ""
 	}
 
+	// the named windows are only for this select, not the sub queries
+	outer := ctx.windows
+	ctx.windows = sb.windows
+	defer func() { ctx.windows = outer }()
+
 	sql := "SELECT"
 
 	if sb.IsDistinct() {
 		sql += " DISTINCT"
 	}
 
-	if sb.IsHasTop() {
-		sql += " TOP " + sb.top
+	if top > 0 && !wrapTop {
+		if !canTop {
+			ctx.addError(ErrUnsupported, "top not support by "+ctx.dialect.Name())
+			return ""
+		}
+		sql += " TOP " + strconv.Itoa(top)
 	}
 
-	sql += " " + strings.Join(sb.selects, ",")
+	sql += " " + ctx.exprs(sb.selects)
 	if sb.IsHasFroms() {
-		sql += " FROM " + strings.Join(sb.froms, ",")
+		sql += " FROM " + ctx.exprs(sb.froms)
 	} else if sb.IsHasTbName() {
-		sql += " FROM " + sb.tbName
+		sql += " FROM " + ctx.ident(sb.tbName)
 	}
 
 	if sb.IsHasJoins() {
-		sql += " " + strings.Join(sb.joins, " ")
+		sql += " " + ctx.joins(sb.joins)
 	}
 
 	if sb.IsHasWheres() {
-		sql += " WHERE " + strings.Join(sb.wheres, " ")
-	}
-
-	if sb.IsHasOrders() {
-		sql += " ORDER BY " + strings.Join(sb.orders, ",")
+		sql += " WHERE " + ctx.conds(sb.wheres)
 	}
 
 	if sb.IsHasGroups() {
-		sql += " GROUP BY " + strings.Join(sb.groups, ",")
+		sql += " GROUP BY " + ctx.exprs(sb.groups)
 	}
 
 	if sb.IsHasHavings() {
-		sql += " HAVING " + sb.havings
+		sql += " HAVING " + ctx.conds(sb.havings)
 	}
 
-	if sb.IsHasLimit() {
-		sql += " LIMIT " + sb.limit
+	sql += sb.windowClause(ctx)
+
+	if sb.IsHasCompounds() {
+		sql += " " + sb.compound(ctx)
+		if wrapTop {
+			if !canTop {
+				ctx.addError(ErrUnsupported, "top not support by "+ctx.dialect.Name())
+				return ""
+			}
+			sql = "SELECT TOP " + strconv.Itoa(top) + " * FROM (" + sql + ") t"
+		} else if isTSQL(ctx.dialect) && !sb.IsHasOrders() && (sb.IsHasLimit() || sb.IsHasOffset()) {
+			// mssql can not `ORDER BY (SELECT NULL)` the compound query, it is wrapped
+			sql = "SELECT * FROM (" + sql + ") t"
+		}
 	}
 
-	sb.buildedStr = sql
+	if sb.IsHasOrders() {
+		sql += " ORDER BY " + ctx.orders(sb.orders)
+	}
 
-	return sb
+	if top == 0 && (sb.IsHasLimit() || sb.IsHasOffset()) {
+		if sql, ok = sb.paging(ctx, sql); !ok {
+			return ""
+		}
+	}
+
+	if sb.IsForUpdate() {
+		if sb.IsHasCompounds() {
+			ctx.addError(ErrUnsupported, "for update can not be with compound query")
+			return ""
+		}
+		lock := ctx.dialect.ForUpdateClause()
+		if lock == "" || style == PageRownum && sb.IsHasLimit() {
+			ctx.addError(ErrUnsupported, "for update not support by "+ctx.dialect.Name())
+			return ""
+		}
+		sql += " " + lock
+	}
+
+	return with + sql
+}
+
+// paging render the limit and offset by the PageStyle() of dialect
+func (sb *SQLBuilder) paging(ctx *buildCtx, sql string) (string, bool) {
+	n, m := strconv.Itoa(sb.limit), strconv.Itoa(sb.offset)
+
+	switch ctx.dialect.PageStyle() {
+	case PageLimit:
+		if !sb.IsHasLimit() {
+			ctx.addError(ErrUnsupported, "offset without limit not support by "+ctx.dialect.Name())
+			return "", false
+		}
+		sql += " LIMIT " + n
+		if sb.IsHasOffset() {
+			sql += " OFFSET " + m
+		}
+	case PageOffsetFetch:
+		// mssql must have `ORDER BY` for `OFFSET`
+		if !sb.IsHasOrders() && isTSQL(ctx.dialect) {
+			sql += " ORDER BY (SELECT NULL)"
+		}
+		sql += " OFFSET " + m + " ROWS"
+		if sb.IsHasLimit() {
+			sql += " FETCH NEXT " + n + " ROWS ONLY"
+		}
+	case PageRownum:
+		if !sb.IsHasOffset() {
+			return "SELECT * FROM (" + sql + ") WHERE ROWNUM <= " + n, true
+		}
+		sql = "SELECT t_.*, ROWNUM rn_ FROM (" + sql + ") t_"
+		if sb.IsHasLimit() {
+			sql += " WHERE ROWNUM <= " + strconv.Itoa(sb.offset+sb.limit)
+		}
+		sql = "SELECT * FROM (" + sql + ") WHERE rn_ > " + m
+	default:
+		ctx.addError(ErrUnsupported, "offset not support by "+ctx.dialect.Name())
+		return "", false
+	}
+
+	return sql, true
 }
 
 // BuildUpdateSQL do build the `update` SQL string
 func (sb *SQLBuilder) BuildUpdateSQL() *SQLBuilder {
+	return sb.buildSQL(sb.buildUpdate)
+}
+
+// BuildUpdate do build the `update` SQL string, return with args and error
+func (sb *SQLBuilder) BuildUpdate() (string, []interface{}, error) {
+	return sb.build(sb.buildUpdate)
+}
+
+func (sb *SQLBuilder) buildUpdate(ctx *buildCtx) string {
+	if !sb.IsHasSets() {
+		ctx.addError(ErrMissingValues, "Without update sets")
+		return ""
+	}
 	if !sb.CanBuildUpdate() {
-		sb.PanicOrErrorLog("Without update table or default TbName")
+		ctx.addError(ErrMissingTable, "Without update table or default TbName")
+		return ""
 	}
 
-	sql := "UPDATE "
-	if sb.IsHasOneFroms() {
-		sql += sb.froms[0] + " "
-	} else if sb.IsHasTbName() {
-		sql += sb.tbName + " "
-	}
-
-	setStr := "SET "
-	for _, set := range sb.sets {
-		switch set.V.(type) {
-		case SQLVar:
-			setStr += fmt.Sprintf("%s=%s,", EscapeStr(set.K, sb.IsMysql()), (set.V.(SQLVar)).VarS)
-		case string:
-			setStr += fmt.Sprintf("%s='%v',", EscapeStr(set.K, sb.IsMysql()), EscapeStr(set.V.(string), sb.IsMysql()))
-		default:
-			if set.V == nil {
-				setStr += fmt.Sprintf("%s=NULL,", EscapeStr(set.K, sb.IsMysql()))
-			} else {
-				setStr += fmt.Sprintf("%s=%v,", EscapeStr(set.K, sb.IsMysql()), set.V)
-			}
+	sql, ok := sb.with(ctx, "UPDATE")
+	if !ok {
+		return ""
+	}
+
+	if sb.IsMultiTable() {
+		multi, ok := sb.multiUpdate(ctx)
+		if !ok {
+			return ""
+		}
+		sql += multi
+	} else {
+		sql += "UPDATE " + sb.table(ctx) + " "
+
+		sql += "SET " + strings.Join(ctx.sets(sb.sets, ""), ",") + sb.output(ctx, "UPDATE")
+
+		if sb.IsHasWheres() {
+			sql += " WHERE " + ctx.conds(sb.wheres)
 		}
 	}
-	sql += strings.Trim(setStr, ",")
 
-	if sb.IsHasWheres() {
-		sql += " WHERE " + strings.Join(sb.wheres, " ")
+	returning, ok := sb.returning(ctx, "UPDATE", 0)
+	if !ok {
+		return ""
 	}
-	sb.buildedStr = sql
 
-	return sb
+	return sql + returning
 }
 
 // BuildInsertSQL do build the `insert` SQL string
 func (sb *SQLBuilder) BuildInsertSQL() *SQLBuilder {
-	if !sb.CanBuildInsert() {
-		sb.PanicOrErrorLog("Without insert table or default TbName")
-	}
+	return sb.buildSQL(sb.buildInsert)
+}
 
-	sql := "INSERT INTO "
-	if sb.IsHasInto() {
-		sql += sb.into
-	} else if sb.IsHasTbName() {
-		sql += sb.tbName
-	}
-
-	sql += " (" + strings.Join(sb.fields, ",") + ") VALUES "
-	vals := "("
-	for _, v := range sb.values[0] {
-		switch v.(type) {
-		case SQLVar:
-			vals += fmt.Sprintf("%s,", v.(SQLVar).VarS)
-		case string:
-			vals += fmt.Sprintf("'%v',", EscapeStr(v.(string), sb.IsMysql()))
-		default:
-			if v == nil {
-				vals += "NULL,"
-			} else {
-				vals += fmt.Sprintf("%v,", v)
-			}
-		}
+// BuildInsert do build the `insert` SQL string, return with args and error
+func (sb *SQLBuilder) BuildInsert() (string, []interface{}, error) {
+	return sb.build(sb.buildInsert)
+}
+
+func (sb *SQLBuilder) buildInsert(ctx *buildCtx) string {
+	if !sb.checkInsert(ctx) {
+		return ""
 	}
-	sql += strings.Trim(vals, ",") + ")"
 
-	sb.buildedStr = sql
+	rows := sb.values
+	if len(rows) > 1 {
+		rows = rows[:1]
+	}
 
-	return sb
+	return sb.insert(ctx, rows)
 }
 
 // BuildBulkInsertSQL do build the `insert` SQL string with bulk values
 func (sb *SQLBuilder) BuildBulkInsertSQL() *SQLBuilder {
-	if !sb.CanBuildInsert() {
-		sb.PanicOrErrorLog("Without insert table or default TbName")
+	return sb.buildSQL(sb.buildBulkInsert)
+}
+
+// BuildBulkInsert do build the `insert` SQL string with bulk values, return with args and error
+func (sb *SQLBuilder) BuildBulkInsert() (string, []interface{}, error) {
+	return sb.build(sb.buildBulkInsert)
+}
+
+func (sb *SQLBuilder) buildBulkInsert(ctx *buildCtx) string {
+	if !sb.checkInsert(ctx) {
+		return ""
 	}
 
-	sql := "INSERT INTO "
-	if sb.IsHasInto() {
-		sql += sb.into
-	} else if sb.IsHasTbName() {
-		sql += sb.tbName
-	}
-
-	sql += " (" + strings.Join(sb.fields, ",") + ") VALUES "
-	vals := ""
-	for _, vs := range sb.values {
-		vals += "("
-		for _, v := range vs {
-			switch v.(type) {
-			case SQLVar:
-				vals += fmt.Sprintf("%s,", v.(SQLVar).VarS)
-			case string:
-				vals += fmt.Sprintf("'%v',", EscapeStr(v.(string), sb.IsMysql()))
-			default:
-				if v == nil {
-					vals += "NULL,"
-				} else {
-					vals += fmt.Sprintf("%v,", v)
-				}
-			}
+	return sb.insert(ctx, sb.values)
+}
+
+// insert render the `insert` with rows or select, and the upsert clause if has
+func (sb *SQLBuilder) insert(ctx *buildCtx, rows [][]interface{}) string {
+	// the `WITH` is before the select of insert, for the dialect that not allow before insert
+	inner := sb.IsHasFromSelect() && ctx.dialect.CTEStyle() != CTEAll
+	sql := ""
+	if !inner {
+		var ok bool
+		if sql, ok = sb.with(ctx, "INSERT"); !ok {
+			return ""
 		}
-		vals = strings.Trim(vals, ",") + "),"
 	}
-	sql += strings.Trim(vals, ",")
 
-	sb.buildedStr = sql
+	if sb.IsHasUpsert() && ctx.dialect.UpsertStyle() == UpsertMerge {
+		merge := sb.buildMergeUpsert(ctx, rows, inner)
+		if merge == "" {
+			return ""
+		}
+		return sql + merge
+	}
 
-	return sb
+	sql += "INSERT INTO " + sb.intoTable(ctx) + " (" + ctx.idents(sb.fields) + ")" + sb.output(ctx, "INSERT")
+	values, ok := sb.insertRows(ctx, rows, inner)
+	if !ok {
+		return ""
+	}
+	sql += values
+
+	if sb.IsHasUpsert() {
+		upsert, ok := sb.upsertClause(ctx)
+		if !ok {
+			return ""
+		}
+		sql += upsert
+	}
+
+	returning, ok := sb.returning(ctx, "INSERT", len(rows))
+	if !ok {
+		return ""
+	}
+
+	return sql + returning
 }
 
 // BuildInsertOrReplaceSQL do build the `insert or replace into` SQL string
-// only for SQLite
+// only for SQLite, or the dialect that embed SQLiteDialect
 func (sb *SQLBuilder) BuildInsertOrReplaceSQL() *SQLBuilder {
-	if !sb.IsSQLite() {
-		sb.PanicOrErrorLog("InsertOrReplace only support SQLite")
+	return sb.buildSQL(sb.buildInsertOrReplace)
+}
+
+// BuildInsertOrReplace do build the `insert or replace into` SQL string, return with args and error
+// only for SQLite, or the dialect that embed SQLiteDialect
+func (sb *SQLBuilder) BuildInsertOrReplace() (string, []interface{}, error) {
+	return sb.build(sb.buildInsertOrReplace)
+}
+
+func (sb *SQLBuilder) buildInsertOrReplace(ctx *buildCtx) string {
+	if ctx.dialect.UpsertStyle() != UpsertOnConflict || !isSQLite(ctx.dialect) {
+		ctx.addError(ErrUnsupported, "InsertOrReplace only support SQLite")
+		return ""
 	}
-	if !sb.CanBuildInsert() {
-		sb.PanicOrErrorLog("Without insert table or default TbName")
+	if !sb.checkInsert(ctx) {
+		return ""
 	}
 
-	sql := "INSERT OR REPLACE INTO "
-	if sb.IsHasInto() {
-		sql += sb.into
-	} else if sb.IsHasTbName() {
-		sql += sb.tbName
+	sql, ok := sb.with(ctx, "INSERT")
+	if !ok {
+		return ""
 	}
 
-	sql += " (" + strings.Join(sb.fields, ",") + ") VALUES "
+	sql += "INSERT OR REPLACE INTO " + sb.intoTable(ctx) + " (" + ctx.idents(sb.fields) + ")"
 
-	vals := "("
-	for _, v := range sb.values[0] {
-		switch v.(type) {
-		case string:
-			vals += fmt.Sprintf("'%v',", EscapeStr(v.(string), sb.IsMysql()))
-		default:
-			if v == nil {
-				vals += "NULL,"
-			} else {
-				vals += fmt.Sprintf("%v,", v)
-			}
-		}
+	rows := sb.values
+	if len(rows) > 1 {
+		rows = rows[:1]
+	}
+	values, ok := sb.insertRows(ctx, rows, false)
+	if !ok {
+		return ""
 	}
-	sql += strings.Trim(vals, ",") + ")"
-	sb.buildedStr = sql
 
-	return sb
+	return sql + values
+}
+
+// checkInsert add the error to ctx if can not build insert
+func (sb *SQLBuilder) checkInsert(ctx *buildCtx) bool {
+	switch {
+	case !(sb.IsHasInto() || sb.IsHasTbName()):
+		ctx.addError(ErrMissingTable, "Without insert table or default TbName")
+	case !sb.IsHasFields():
+		ctx.addError(ErrMissingFields, "Without insert fields")
+	case !sb.IsHasValues() && !sb.IsHasFromSelect():
+		ctx.addError(ErrMissingValues, "Without insert values")
+	case sb.IsHasValues() && sb.IsHasFromSelect():
+		ctx.addError(ErrInvalidArgument, "insert values and select can not be both set")
+	default:
+		return true
+	}
+
+	return false
 }
 
 // CanBuildSelect is internal function
@@ -515,7 +780,7 @@ func (sb *SQLBuilder) CanBuildDelete() bool {
 // CanBuildUpdate is internal function
 // that sqlbuilder to know can build update string or not
 func (sb *SQLBuilder) CanBuildUpdate() bool {
-	return (sb.IsHasOneFroms() || sb.IsHasTbName()) && sb.IsHasSets()
+	return (sb.IsHasFroms() || sb.IsHasTbName()) && sb.IsHasSets()
 }
 
 // CanBuildInsert is internal function
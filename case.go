@@ -0,0 +1,79 @@
+// Author :		Eric<eehsiao@gmail.com>
+
+package sqlbuilder
+
+import (
+	"strings"
+)
+
+// CaseExpr is the `CASE WHEN ... THEN ... ELSE ... END` expression
+// the values are escaped or bind as placeholder, as same as the values of Where()
+type CaseExpr struct {
+	whens []caseWhen
+	els   Expr
+}
+
+type caseWhen struct {
+	cond SubCond
+	v    Expr
+}
+
+// Case can create a `case` expression, it can use in SelectExpr(), OrderByExpr(), GroupByExpr(),
+// the value of Set() and Where() or the operand of WhereExpr()
+// ex :
+// ```
+// Set([]Set{{K: "status", V: Case().When(On("qty", "=", 0), "sold out").Else("on sale")}})
+// ```
+func Case() *CaseExpr {
+	return &CaseExpr{}
+}
+
+// When add a `WHEN cond THEN v`, the cond can be a group, ex : And()
+func (c *CaseExpr) When(cond SubCond, v interface{}) *CaseExpr {
+	c.whens = append(c.whens, caseWhen{cond: cond, v: valueExpr(v)})
+
+	return c
+}
+
+// Else set the `ELSE v`
+func (c *CaseExpr) Else(v interface{}) *CaseExpr {
+	c.els = valueExpr(v)
+
+	return c
+}
+
+func (c *CaseExpr) build(ctx *buildCtx) string {
+	if len(c.whens) == 0 {
+		ctx.addError(ErrMissingCondition, "case without when")
+		return ""
+	}
+
+	strs := make([]string, 0, len(c.whens)+2)
+	strs = append(strs, "CASE")
+	for _, w := range c.whens {
+		strs = append(strs, "WHEN "+ctx.cond(w.cond)+" THEN "+w.v.build(ctx))
+	}
+	if c.els != nil {
+		strs = append(strs, "ELSE "+c.els.build(ctx))
+	}
+
+	return strings.Join(append(strs, "END"), " ")
+}
+
+// OnExpr return a sub condition that the left operand is a expression, ex : Case()
+func OnExpr(e Expr, o string, v interface{}) SubCond {
+	return SubCond{c: true, e: binary{l: e, o: o, r: valueExpr(v)}}
+}
+
+// WhereExpr add a condition that the left operand is a expression, ex : Case()
+// if this not first time use, its will be `and` condition
+func (sb *SQLBuilder) WhereExpr(e Expr, o string, v interface{}) *SQLBuilder {
+	if e == nil || o == "" {
+		sb.addError(ErrMissingCondition, "must be support conditions")
+		return sb
+	}
+
+	sb.wheres = append(sb.wheres, OnExpr(e, o, v))
+
+	return sb
+}
@@ -0,0 +1,104 @@
+// Author :		Eric<eehsiao@gmail.com>
+
+package sqlbuilder
+
+import (
+	"strings"
+)
+
+// compound is a set operation with other select, ex : `UNION ALL`
+type compound struct {
+	op string
+	sb *SQLBuilder
+}
+
+// the set operations
+const (
+	opUnion     = "UNION"
+	opUnionAll  = "UNION ALL"
+	opIntersect = "INTERSECT"
+	opExcept    = "EXCEPT"
+)
+
+func (sb *SQLBuilder) compoundWith(op string, qs ...*SQLBuilder) *SQLBuilder {
+	if len(qs) == 0 {
+		sb.addError(ErrInvalidArgument, "must have query for "+strings.ToLower(op))
+		return sb
+	}
+
+	for _, q := range qs {
+		if q == nil {
+			sb.addError(ErrInvalidArgument, "must have query for "+strings.ToLower(op))
+			return sb
+		}
+		sb.compounds = append(sb.compounds, compound{op: op, sb: q})
+	}
+
+	return sb
+}
+
+// Union add `UNION` with other selects
+// the OrderBy(), Limit() ... of this builder are for the compound result,
+// the query that has its own order by or limit is in parentheses
+// ex :
+// ```
+// Select("id").From("live").Union(NewSQLBuilder().Select("id").From("archive")).OrderBy("id").Limit(10)
+// ```
+// is `SELECT id FROM live UNION SELECT id FROM archive ORDER BY id ASC LIMIT 10`
+func (sb *SQLBuilder) Union(qs ...*SQLBuilder) *SQLBuilder {
+	return sb.compoundWith(opUnion, qs...)
+}
+
+// UnionAll add `UNION ALL` with other selects
+func (sb *SQLBuilder) UnionAll(qs ...*SQLBuilder) *SQLBuilder {
+	return sb.compoundWith(opUnionAll, qs...)
+}
+
+// Intersect add `INTERSECT` with other selects
+func (sb *SQLBuilder) Intersect(qs ...*SQLBuilder) *SQLBuilder {
+	return sb.compoundWith(opIntersect, qs...)
+}
+
+// Except add `EXCEPT` with other selects, it is `MINUS` for oracle
+func (sb *SQLBuilder) Except(qs ...*SQLBuilder) *SQLBuilder {
+	return sb.compoundWith(opExcept, qs...)
+}
+
+// IsHasCompounds is internal function
+func (sb *SQLBuilder) IsHasCompounds() bool {
+	return len(sb.compounds) > 0
+}
+
+// compound render the set operations
+func (sb *SQLBuilder) compound(ctx *buildCtx) string {
+	strs := make([]string, 0, len(sb.compounds))
+	for _, c := range sb.compounds {
+		op := c.op
+		if op == opExcept {
+			op = ctx.dialect.ExceptKeyword()
+		}
+		strs = append(strs, op+" "+c.operand(ctx))
+	}
+
+	return strings.Join(strs, " ")
+}
+
+// operand render the select of set operation
+// it is in parentheses when it has the clauses that belong to a compound result
+func (c compound) operand(ctx *buildCtx) string {
+	if len(c.sb.errs) > 0 {
+		ctx.errs = append(ctx.errs, c.sb.errs...)
+		return ""
+	}
+
+	str := c.sb.buildSelect(ctx)
+	if !c.sb.IsHasOrders() && !c.sb.IsHasLimit() && !c.sb.IsHasOffset() && !c.sb.IsHasTop() &&
+		!c.sb.IsHasCompounds() && !c.sb.IsHasWith() {
+		return str
+	}
+	if ctx.dialect.ParenCompound() {
+		return "(" + str + ")"
+	}
+
+	return "SELECT * FROM (" + str + ")"
+}
@@ -0,0 +1,70 @@
+// Author :		Eric<eehsiao@gmail.com>
+
+package sqlbuilder
+
+// CountQuery return a new builder that count the rows of this select builder
+// the orders, limit, offset, top, SeekAfter() and for update are dropped,
+// it is `SELECT COUNT(*) FROM ... WHERE ...`,
+// or `SELECT COUNT(*) FROM (...) t` when there is distinct, group by, having or compound
+// ex :
+// ```
+// total := b.CountQuery().BuildSelectSQL().BuildedSQL()
+// rows := b.Limit(10).BuildSelectSQL().BuildedSQL()
+// ```
+func (sb *SQLBuilder) CountQuery() *SQLBuilder {
+	inner := sb.clone()
+	inner.orders = make([]order, 0)
+	inner.limit, inner.offset, inner.top = 0, 0, 0
+	// the seek is pagination also, the count is the total of all pages
+	inner.wheres, _ = splitSeeks(inner.wheres)
+	inner.forUpdate = false
+
+	count := []Expr{Fn("COUNT", Var("*"))}
+	if !sb.IsDistinct() && !sb.IsHasGroups() && !sb.IsHasHavings() && !sb.IsHasCompounds() {
+		inner.selects = count
+		return inner
+	}
+
+	c := sb.clone()
+	c.ClearBuilder()
+	c.errs = append(c.errs, inner.errs...)
+	c.ctes, inner.ctes = inner.ctes, make([]cte, 0)
+	c.selects = count
+	c.froms = []Expr{Sub(inner, "t")}
+
+	return c
+}
+
+// clone return a copy of builder, the clauses are not shared with the original
+func (sb *SQLBuilder) clone() *SQLBuilder {
+	c := *sb
+	c.ctes = append([]cte(nil), sb.ctes...)
+	c.selects = append([]Expr(nil), sb.selects...)
+	c.froms = append([]Expr(nil), sb.froms...)
+	c.joins = append([]join(nil), sb.joins...)
+	c.wheres = append([]SubCond(nil), sb.wheres...)
+	c.orders = append([]order(nil), sb.orders...)
+	c.groups = append([]Expr(nil), sb.groups...)
+	c.havings = append([]SubCond(nil), sb.havings...)
+	c.windows = append([]window(nil), sb.windows...)
+	c.compounds = append([]compound(nil), sb.compounds...)
+	c.fields = append([]string(nil), sb.fields...)
+	c.values = append([][]interface{}(nil), sb.values...)
+	c.sets = append([]Set(nil), sb.sets...)
+	c.returnings = append([]string(nil), sb.returnings...)
+	if sb.upsert != nil {
+		u := *sb.upsert
+		c.upsert = &u
+	}
+	if sb.merge != nil {
+		m := *sb.merge
+		m.whens = append([]mergeWhen(nil), sb.merge.whens...)
+		c.merge = &m
+	}
+	c.errs = append([]error(nil), sb.errs...)
+	c.buildErrs = make([]error, 0)
+	c.buildedStr = ""
+	c.buildedArgs = make([]interface{}, 0)
+
+	return &c
+}
@@ -0,0 +1,112 @@
+// Author :		Eric<eehsiao@gmail.com>
+
+package sqlbuilder
+
+import (
+	"strings"
+)
+
+// cte is a common table expression of `WITH`
+// rec is the recursive part, it is `(sb UNION ALL rec)` when not nil
+type cte struct {
+	name string
+	cols []string
+	sb   *SQLBuilder
+	rec  *SQLBuilder
+}
+
+// With add a common table expression, it is rendered before the statement
+// ex :
+// ```
+// With("t", NewSQLBuilder().Select("id").From("tblA")).Select("id").From("t")
+// ```
+// is `WITH t AS (SELECT id FROM tblA) SELECT id FROM t`
+func (sb *SQLBuilder) With(name string, q *SQLBuilder) *SQLBuilder {
+	if name == "" || q == nil {
+		sb.addError(ErrInvalidArgument, "must have name and query for with")
+		return sb
+	}
+
+	sb.ctes = append(sb.ctes, cte{name: name, sb: q})
+
+	return sb
+}
+
+// WithRecursive add a recursive common table expression
+// the anchor and recursive queries are union all, the recursive query select from name
+// the `RECURSIVE` keyword is only rendered for the dialect that need it
+// ex :
+// ```
+// anchor := NewSQLBuilder().Select("id", "parent_id").From("category").Where("id", "=", 1)
+// rec := NewSQLBuilder().Select("c.id", "c.parent_id").From("category c").JoinOn("tree t", "t.id", "=", Var("c.parent_id"))
+// WithRecursive("tree", []string{"id", "parent_id"}, anchor, rec).Select("id").From("tree")
+// ```
+func (sb *SQLBuilder) WithRecursive(name string, cols []string, anchor *SQLBuilder, recursive *SQLBuilder) *SQLBuilder {
+	if name == "" || anchor == nil || recursive == nil {
+		sb.addError(ErrInvalidArgument, "must have name, anchor and recursive query for with recursive")
+		return sb
+	}
+	if len(cols) == 0 {
+		sb.addError(ErrMissingFields, "must have columns for with recursive")
+		return sb
+	}
+
+	sb.ctes = append(sb.ctes, cte{name: name, cols: cols, sb: anchor, rec: recursive})
+
+	return sb
+}
+
+// IsHasWith is internal function
+func (sb *SQLBuilder) IsHasWith() bool {
+	return len(sb.ctes) > 0
+}
+
+// with render the `WITH` clause before the stmt, ex : `SELECT`
+// it is empty when there is no cte
+func (sb *SQLBuilder) with(ctx *buildCtx, stmt string) (string, bool) {
+	if !sb.IsHasWith() {
+		return "", true
+	}
+
+	switch ctx.dialect.CTEStyle() {
+	case CTENoInsert:
+		if stmt == "INSERT" {
+			ctx.addError(ErrUnsupported, "with before insert not support by "+ctx.dialect.Name())
+			return "", false
+		}
+	case CTESelectOnly:
+		if stmt != "SELECT" {
+			ctx.addError(ErrUnsupported, "with before "+strings.ToLower(stmt)+" not support by "+ctx.dialect.Name())
+			return "", false
+		}
+	}
+
+	sql := "WITH "
+	strs := make([]string, 0, len(sb.ctes))
+	for _, c := range sb.ctes {
+		if c.rec != nil && ctx.dialect.RecursiveKeyword() != "" {
+			sql = "WITH " + ctx.dialect.RecursiveKeyword() + " "
+		}
+		strs = append(strs, c.build(ctx))
+	}
+
+	return sql + strings.Join(strs, ",") + " ", true
+}
+
+func (c cte) build(ctx *buildCtx) string {
+	str := ctx.ident(c.name)
+	if len(c.cols) > 0 {
+		str += "(" + ctx.idents(c.cols) + ")"
+	}
+
+	q := subquery{sb: c.sb}.build(ctx)
+	if c.rec != nil {
+		rec := subquery{sb: c.rec}.build(ctx)
+		if q == "" || rec == "" {
+			return ""
+		}
+		q = q[:len(q)-1] + " UNION ALL " + rec[1:]
+	}
+
+	return str + " AS " + q
+}
@@ -0,0 +1,532 @@
+// Author :		Eric<eehsiao@gmail.com>
+
+package sqlbuilder
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// PageStyle is how a dialect render the pagination
+type PageStyle int
+
+// the pagination styles
+const (
+	// PageLimit is `LIMIT n`
+	PageLimit PageStyle = iota
+	// PageTop is `SELECT TOP n`
+	PageTop
+	// PageOffsetFetch is `OFFSET m ROWS FETCH NEXT n ROWS ONLY`
+	PageOffsetFetch
+	// PageRownum is wrap the query with `ROWNUM`
+	PageRownum
+)
+
+// UpsertStyle is how a dialect render the insert or update
+type UpsertStyle int
+
+// the upsert styles
+const (
+	// UpsertOnDuplicateKey is `ON DUPLICATE KEY UPDATE`
+	UpsertOnDuplicateKey UpsertStyle = iota
+	// UpsertOnConflict is `ON CONFLICT ... DO UPDATE SET`
+	UpsertOnConflict
+	// UpsertMerge is `MERGE INTO ... USING ...`
+	UpsertMerge
+)
+
+// CTEStyle is where a dialect allow the `WITH` clause
+type CTEStyle int
+
+// the cte styles
+const (
+	// CTEAll is `WITH` before select, insert, update and delete
+	CTEAll CTEStyle = iota
+	// CTENoInsert is `WITH` before select, update and delete, but not insert
+	CTENoInsert
+	// CTESelectOnly is `WITH` only before select
+	CTESelectOnly
+)
+
+// MergeStyle is how a dialect render the `MERGE` statement
+type MergeStyle int
+
+// the merge styles
+const (
+	// MergeNone is not support `MERGE`
+	MergeNone MergeStyle = iota
+	// MergeWhenAnd is `WHEN MATCHED AND cond THEN ...`, with `WHEN NOT MATCHED BY SOURCE`
+	MergeWhenAnd
+	// MergeWhere is `WHEN MATCHED THEN UPDATE ... WHERE cond DELETE WHERE cond`
+	MergeWhere
+)
+
+// ReturningStyle is how a dialect render the returning of insert, update and delete
+type ReturningStyle int
+
+// the returning styles
+const (
+	// ReturningNone is not support the returning
+	ReturningNone ReturningStyle = iota
+	// ReturningClause is `RETURNING cols` at the end
+	ReturningClause
+	// ReturningInsertDelete is `RETURNING cols` at the end, only for insert and delete
+	ReturningInsertDelete
+	// ReturningOutput is `OUTPUT INSERTED.col` before `VALUES` or `WHERE`
+	ReturningOutput
+	// ReturningInto is `RETURNING cols INTO :n` with out placeholders
+	ReturningInto
+)
+
+var (
+	// mysqlEscaper escape the string literal of mysql, the backslash is a escape char in it
+	mysqlEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`, `"`, `\"`)
+	// standardEscaper escape the string literal of the standard sql
+	standardEscaper = strings.NewReplacer(`'`, `''`)
+)
+
+// MultiTableStyle is how a dialect render the update and delete with join
+type MultiTableStyle int
+
+// the multi table styles
+const (
+	// MultiTableNone is not support the update and delete with join
+	MultiTableNone MultiTableStyle = iota
+	// MultiTableJoin is `UPDATE a JOIN b ON ... SET` and `DELETE a FROM a JOIN b ON ...`
+	MultiTableJoin
+	// MultiTableFromJoin is `UPDATE a SET ... FROM a JOIN b ON ...` and `DELETE a FROM a JOIN b ON ...`
+	MultiTableFromJoin
+	// MultiTableUsing is `UPDATE a SET ... FROM b WHERE` and `DELETE FROM a USING b WHERE`
+	MultiTableUsing
+	// MultiTableUpdateFrom is `UPDATE a SET ... FROM b WHERE`, without the delete
+	MultiTableUpdateFrom
+)
+
+// Dialect is the sql engine behavior of the builder
+// the built-in dialects can be embedded to make a new flavour,
+// then add it via RegisterDialect()
+type Dialect interface {
+	// Name return the driver type name, ex : `mysql`
+	Name() string
+	// QuoteIdent quote one part of identifier, ex : `col`
+	QuoteIdent(s string) string
+	// EscapeStr escape the string value
+	EscapeStr(s string) string
+	// Placeholder return the n-th (start from 1) placeholder
+	Placeholder(n int) string
+	// BoolLiteral render the bool value
+	BoolLiteral(b bool) string
+	// PageStyle return the pagination style
+	PageStyle() PageStyle
+	// UpsertStyle return the insert or update style
+	UpsertStyle() UpsertStyle
+	// ForUpdateClause return the row locking clause of select, empty if not support
+	ForUpdateClause() string
+	// RowValues return true if support the row values compare, ex : `(a,b) > (1,2)`
+	RowValues() bool
+	// CTEStyle return where the `WITH` clause is allowed
+	CTEStyle() CTEStyle
+	// RecursiveKeyword return the keyword after `WITH` of recursive cte, empty if not need
+	RecursiveKeyword() string
+	// ExceptKeyword return the keyword of `EXCEPT` set operation, ex : `MINUS`
+	ExceptKeyword() string
+	// ParenCompound return true if the operand of compound query can be in parentheses,
+	// else it is wrapped as `SELECT * FROM (...)`
+	ParenCompound() bool
+	// NamedWindow return true if support the `WINDOW w AS (...)` clause
+	NamedWindow() bool
+	// DualTable return the dummy table of select without table, ex : `dual`, empty if not need
+	DualTable() string
+	// MergeStyle return the `MERGE` statement style
+	MergeStyle() MergeStyle
+	// ReturningStyle return the returning style of insert, update and delete
+	ReturningStyle() ReturningStyle
+	// MultiTableStyle return the style of update and delete with join
+	MultiTableStyle() MultiTableStyle
+}
+
+var (
+	dialectsMu sync.RWMutex
+	dialects   = map[string]Dialect{}
+)
+
+func init() {
+	RegisterDialect(MysqlDialect{})
+	RegisterDialect(MariadbDialect{})
+	RegisterDialect(MssqlDialect{})
+	RegisterDialect(OracleDialect{})
+	RegisterDialect(PostgresqlDialect{})
+	RegisterDialect(SQLiteDialect{})
+}
+
+// RegisterDialect add or replace a dialect by its Name()
+// then it can be use via NewSQLBuilder(name) or SetDriverType(name)
+func RegisterDialect(d Dialect) {
+	if d == nil || d.Name() == "" {
+		return
+	}
+
+	dialectsMu.Lock()
+	defer dialectsMu.Unlock()
+	dialects[d.Name()] = d
+}
+
+func getDialect(name string) (d Dialect, ok bool) {
+	dialectsMu.RLock()
+	defer dialectsMu.RUnlock()
+	d, ok = dialects[name]
+
+	return
+}
+
+// isTSQL check the dialect is mssql or embed MssqlDialect
+func isTSQL(d Dialect) bool {
+	_, ok := d.(interface{ tsql() })
+
+	return ok
+}
+
+// isSQLite check the dialect is SQLite or embed SQLiteDialect
+func isSQLite(d Dialect) bool {
+	_, ok := d.(interface{ sqlite() })
+
+	return ok
+}
+
+func quoteWith(s string, l string, r string) string {
+	return l + strings.Replace(s, r, r+r, -1) + r
+}
+
+// MysqlDialect is the built-in `mysql` dialect
+type MysqlDialect struct{}
+
+// Name return `mysql`
+func (MysqlDialect) Name() string { return "mysql" }
+
+// QuoteIdent quote with backtick
+func (MysqlDialect) QuoteIdent(s string) string { return quoteWith(s, "`", "`") }
+
+// EscapeStr escape the backslash and quotes with backslash
+func (MysqlDialect) EscapeStr(s string) string { return mysqlEscaper.Replace(s) }
+
+// Placeholder return `?`
+func (MysqlDialect) Placeholder(n int) string { return "?" }
+
+// BoolLiteral return `true` or `false`
+func (MysqlDialect) BoolLiteral(b bool) string { return strconv.FormatBool(b) }
+
+// PageStyle return PageLimit
+func (MysqlDialect) PageStyle() PageStyle { return PageLimit }
+
+// UpsertStyle return UpsertOnDuplicateKey
+func (MysqlDialect) UpsertStyle() UpsertStyle { return UpsertOnDuplicateKey }
+
+// ForUpdateClause return `FOR UPDATE`
+func (MysqlDialect) ForUpdateClause() string { return "FOR UPDATE" }
+
+// RowValues return true
+func (MysqlDialect) RowValues() bool { return true }
+
+// CTEStyle return CTENoInsert
+func (MysqlDialect) CTEStyle() CTEStyle { return CTENoInsert }
+
+// RecursiveKeyword return `RECURSIVE`
+func (MysqlDialect) RecursiveKeyword() string { return "RECURSIVE" }
+
+// ExceptKeyword return `EXCEPT`
+func (MysqlDialect) ExceptKeyword() string { return "EXCEPT" }
+
+// ParenCompound return true
+func (MysqlDialect) ParenCompound() bool { return true }
+
+// NamedWindow return true
+func (MysqlDialect) NamedWindow() bool { return true }
+
+// DualTable return empty
+func (MysqlDialect) DualTable() string { return "" }
+
+// MergeStyle return MergeNone
+func (MysqlDialect) MergeStyle() MergeStyle { return MergeNone }
+
+// ReturningStyle return ReturningNone
+func (MysqlDialect) ReturningStyle() ReturningStyle { return ReturningNone }
+
+// MultiTableStyle return MultiTableJoin
+func (MysqlDialect) MultiTableStyle() MultiTableStyle { return MultiTableJoin }
+
+// MariadbDialect is the built-in `mariadb` dialect
+// it is same as mysql, except the returning
+type MariadbDialect struct {
+	MysqlDialect
+}
+
+// Name return `mariadb`
+func (MariadbDialect) Name() string { return "mariadb" }
+
+// ReturningStyle return ReturningInsertDelete
+func (MariadbDialect) ReturningStyle() ReturningStyle { return ReturningInsertDelete }
+
+// MssqlDialect is the built-in `mssql` dialect
+// LegacyPaging is for the server before 2012, that only `TOP n` and without offset
+// ex :
+// ```
+// RegisterDialect(MssqlDialect{LegacyPaging: true})
+// ```
+type MssqlDialect struct {
+	LegacyPaging bool
+}
+
+// Name return `mssql`
+func (MssqlDialect) Name() string { return "mssql" }
+
+// QuoteIdent quote with brackets
+func (MssqlDialect) QuoteIdent(s string) string { return quoteWith(s, "[", "]") }
+
+// EscapeStr escape the single quote with double single quote
+func (MssqlDialect) EscapeStr(s string) string { return standardEscaper.Replace(s) }
+
+// Placeholder return `@pN`
+func (MssqlDialect) Placeholder(n int) string { return "@p" + strconv.Itoa(n) }
+
+// BoolLiteral return `1` or `0`
+func (MssqlDialect) BoolLiteral(b bool) string { return boolDigit(b) }
+
+// PageStyle return PageOffsetFetch, or PageTop when LegacyPaging
+func (d MssqlDialect) PageStyle() PageStyle {
+	if d.LegacyPaging {
+		return PageTop
+	}
+
+	return PageOffsetFetch
+}
+
+// UpsertStyle return UpsertMerge
+func (MssqlDialect) UpsertStyle() UpsertStyle { return UpsertMerge }
+
+// ForUpdateClause return empty, mssql lock via table hints
+func (MssqlDialect) ForUpdateClause() string { return "" }
+
+// RowValues return false
+func (MssqlDialect) RowValues() bool { return false }
+
+// CTEStyle return CTEAll
+func (MssqlDialect) CTEStyle() CTEStyle { return CTEAll }
+
+// RecursiveKeyword return empty, mssql cte is recursive without keyword
+func (MssqlDialect) RecursiveKeyword() string { return "" }
+
+// ExceptKeyword return `EXCEPT`
+func (MssqlDialect) ExceptKeyword() string { return "EXCEPT" }
+
+// ParenCompound return true
+func (MssqlDialect) ParenCompound() bool { return true }
+
+// NamedWindow return false, the window spec is inline
+func (MssqlDialect) NamedWindow() bool { return false }
+
+// DualTable return empty
+func (MssqlDialect) DualTable() string { return "" }
+
+// MergeStyle return MergeWhenAnd
+func (MssqlDialect) MergeStyle() MergeStyle { return MergeWhenAnd }
+
+// ReturningStyle return ReturningOutput
+func (MssqlDialect) ReturningStyle() ReturningStyle { return ReturningOutput }
+
+// MultiTableStyle return MultiTableFromJoin
+func (MssqlDialect) MultiTableStyle() MultiTableStyle { return MultiTableFromJoin }
+
+// tsql mark the mssql family, it has `TOP n`
+func (MssqlDialect) tsql() {}
+
+// OracleDialect is the built-in `oracle` dialect
+// LegacyPaging is for the server before 12c, that wrap the query with `ROWNUM`
+type OracleDialect struct {
+	LegacyPaging bool
+}
+
+// Name return `oracle`
+func (OracleDialect) Name() string { return "oracle" }
+
+// QuoteIdent quote with double quote
+func (OracleDialect) QuoteIdent(s string) string { return quoteWith(s, `"`, `"`) }
+
+// EscapeStr escape the single quote with double single quote
+func (OracleDialect) EscapeStr(s string) string { return standardEscaper.Replace(s) }
+
+// Placeholder return `:N`
+func (OracleDialect) Placeholder(n int) string { return ":" + strconv.Itoa(n) }
+
+// BoolLiteral return `1` or `0`
+func (OracleDialect) BoolLiteral(b bool) string { return boolDigit(b) }
+
+// PageStyle return PageOffsetFetch, or PageRownum when LegacyPaging
+func (d OracleDialect) PageStyle() PageStyle {
+	if d.LegacyPaging {
+		return PageRownum
+	}
+
+	return PageOffsetFetch
+}
+
+// UpsertStyle return UpsertMerge
+func (OracleDialect) UpsertStyle() UpsertStyle { return UpsertMerge }
+
+// ForUpdateClause return `FOR UPDATE`
+func (OracleDialect) ForUpdateClause() string { return "FOR UPDATE" }
+
+// RowValues return false, oracle only compare row values by equal
+func (OracleDialect) RowValues() bool { return false }
+
+// CTEStyle return CTESelectOnly
+func (OracleDialect) CTEStyle() CTEStyle { return CTESelectOnly }
+
+// RecursiveKeyword return empty, oracle cte is recursive without keyword
+func (OracleDialect) RecursiveKeyword() string { return "" }
+
+// ExceptKeyword return `MINUS`
+func (OracleDialect) ExceptKeyword() string { return "MINUS" }
+
+// ParenCompound return true
+func (OracleDialect) ParenCompound() bool { return true }
+
+// NamedWindow return false, the window spec is inline
+func (OracleDialect) NamedWindow() bool { return false }
+
+// DualTable return `dual`
+func (OracleDialect) DualTable() string { return "dual" }
+
+// MergeStyle return MergeWhere
+func (OracleDialect) MergeStyle() MergeStyle { return MergeWhere }
+
+// ReturningStyle return ReturningInto
+func (OracleDialect) ReturningStyle() ReturningStyle { return ReturningInto }
+
+// MultiTableStyle return MultiTableNone
+func (OracleDialect) MultiTableStyle() MultiTableStyle { return MultiTableNone }
+
+// PostgresqlDialect is the built-in `postgresql` dialect
+type PostgresqlDialect struct{}
+
+// Name return `postgresql`
+func (PostgresqlDialect) Name() string { return "postgresql" }
+
+// QuoteIdent quote with double quote
+func (PostgresqlDialect) QuoteIdent(s string) string { return quoteWith(s, `"`, `"`) }
+
+// EscapeStr escape the single quote with double single quote
+func (PostgresqlDialect) EscapeStr(s string) string { return standardEscaper.Replace(s) }
+
+// Placeholder return `$N`
+func (PostgresqlDialect) Placeholder(n int) string { return "$" + strconv.Itoa(n) }
+
+// BoolLiteral return `true` or `false`
+func (PostgresqlDialect) BoolLiteral(b bool) string { return strconv.FormatBool(b) }
+
+// PageStyle return PageLimit
+func (PostgresqlDialect) PageStyle() PageStyle { return PageLimit }
+
+// UpsertStyle return UpsertOnConflict
+func (PostgresqlDialect) UpsertStyle() UpsertStyle { return UpsertOnConflict }
+
+// ForUpdateClause return `FOR UPDATE`
+func (PostgresqlDialect) ForUpdateClause() string { return "FOR UPDATE" }
+
+// RowValues return true
+func (PostgresqlDialect) RowValues() bool { return true }
+
+// CTEStyle return CTEAll
+func (PostgresqlDialect) CTEStyle() CTEStyle { return CTEAll }
+
+// RecursiveKeyword return `RECURSIVE`
+func (PostgresqlDialect) RecursiveKeyword() string { return "RECURSIVE" }
+
+// ExceptKeyword return `EXCEPT`
+func (PostgresqlDialect) ExceptKeyword() string { return "EXCEPT" }
+
+// ParenCompound return true
+func (PostgresqlDialect) ParenCompound() bool { return true }
+
+// NamedWindow return true
+func (PostgresqlDialect) NamedWindow() bool { return true }
+
+// DualTable return empty
+func (PostgresqlDialect) DualTable() string { return "" }
+
+// MergeStyle return MergeNone
+func (PostgresqlDialect) MergeStyle() MergeStyle { return MergeNone }
+
+// ReturningStyle return ReturningClause
+func (PostgresqlDialect) ReturningStyle() ReturningStyle { return ReturningClause }
+
+// MultiTableStyle return MultiTableUsing
+func (PostgresqlDialect) MultiTableStyle() MultiTableStyle { return MultiTableUsing }
+
+// SQLiteDialect is the built-in `SQLite` dialect
+type SQLiteDialect struct{}
+
+// Name return `SQLite`
+func (SQLiteDialect) Name() string { return "SQLite" }
+
+// QuoteIdent quote with double quote
+func (SQLiteDialect) QuoteIdent(s string) string { return quoteWith(s, `"`, `"`) }
+
+// EscapeStr escape the single quote with double single quote
+func (SQLiteDialect) EscapeStr(s string) string { return standardEscaper.Replace(s) }
+
+// Placeholder return `?`
+func (SQLiteDialect) Placeholder(n int) string { return "?" }
+
+// BoolLiteral return `1` or `0`
+func (SQLiteDialect) BoolLiteral(b bool) string { return boolDigit(b) }
+
+// PageStyle return PageLimit
+func (SQLiteDialect) PageStyle() PageStyle { return PageLimit }
+
+// UpsertStyle return UpsertOnConflict
+func (SQLiteDialect) UpsertStyle() UpsertStyle { return UpsertOnConflict }
+
+// ForUpdateClause return empty, SQLite lock the whole database
+func (SQLiteDialect) ForUpdateClause() string { return "" }
+
+// RowValues return true, since SQLite 3.15
+func (SQLiteDialect) RowValues() bool { return true }
+
+// CTEStyle return CTEAll
+func (SQLiteDialect) CTEStyle() CTEStyle { return CTEAll }
+
+// RecursiveKeyword return `RECURSIVE`
+func (SQLiteDialect) RecursiveKeyword() string { return "RECURSIVE" }
+
+// ExceptKeyword return `EXCEPT`
+func (SQLiteDialect) ExceptKeyword() string { return "EXCEPT" }
+
+// ParenCompound return false, SQLite not allow the parentheses
+func (SQLiteDialect) ParenCompound() bool { return false }
+
+// NamedWindow return true
+func (SQLiteDialect) NamedWindow() bool { return true }
+
+// DualTable return empty
+func (SQLiteDialect) DualTable() string { return "" }
+
+// MergeStyle return MergeNone
+func (SQLiteDialect) MergeStyle() MergeStyle { return MergeNone }
+
+// ReturningStyle return ReturningClause, since SQLite 3.35
+func (SQLiteDialect) ReturningStyle() ReturningStyle { return ReturningClause }
+
+// MultiTableStyle return MultiTableUpdateFrom, since SQLite 3.33
+func (SQLiteDialect) MultiTableStyle() MultiTableStyle { return MultiTableUpdateFrom }
+
+// sqlite mark the SQLite family, it has `INSERT OR REPLACE`
+func (SQLiteDialect) sqlite() {}
+
+func boolDigit(b bool) string {
+	if b {
+		return "1"
+	}
+
+	return "0"
+}
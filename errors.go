@@ -0,0 +1,130 @@
+// Author :		Eric<eehsiao@gmail.com>
+
+package sqlbuilder
+
+import (
+	"errors"
+	"log"
+)
+
+// the sentinel errors, can check them via errors.Is()
+var (
+	ErrMissingTable     = errors.New("sqlbuilder: missing table")
+	ErrMissingFields    = errors.New("sqlbuilder: missing fields")
+	ErrMissingValues    = errors.New("sqlbuilder: missing values")
+	ErrMissingCondition = errors.New("sqlbuilder: missing condition")
+	ErrMissingGroupBy   = errors.New("sqlbuilder: missing group by")
+	ErrFieldValueCount  = errors.New("sqlbuilder: fields count not equal values count")
+	ErrInvalidArgument  = errors.New("sqlbuilder: invalid argument")
+	ErrUnsupported      = errors.New("sqlbuilder: unsupported clause for dialect")
+)
+
+// ErrorPolicy is how the builder handle the errors in Build...SQL()
+type ErrorPolicy int
+
+// the error policies
+const (
+	// ErrorPanic is panic with the error, it is the default
+	ErrorPanic ErrorPolicy = iota
+	// ErrorLog is write the error to logger
+	ErrorLog
+	// ErrorCollect is only keep the error on builder, can get via Err()
+	ErrorCollect
+)
+
+// BuildError is the error that recorded on builder
+// Err is one of the sentinel errors
+type BuildError struct {
+	Err error
+	Msg string
+}
+
+// Error return the message of error
+func (e *BuildError) Error() string {
+	if e.Msg == "" {
+		return e.Err.Error()
+	}
+
+	return e.Err.Error() + ": " + e.Msg
+}
+
+// Unwrap return the sentinel error
+func (e *BuildError) Unwrap() error {
+	return e.Err
+}
+
+// SetErrorPolicy set how the builder handle the errors in Build...SQL()
+// it only for this builder
+func (sb *SQLBuilder) SetErrorPolicy(p ErrorPolicy) *SQLBuilder {
+	sb.errPolicy = p
+
+	return sb
+}
+
+// GetErrorPolicy return the ErrorPolicy of builder
+func (sb *SQLBuilder) GetErrorPolicy() ErrorPolicy {
+	return sb.errPolicy
+}
+
+// SetLogger set the logger for ErrorLog policy, default write to stderr
+func (sb *SQLBuilder) SetLogger(l *log.Logger) *SQLBuilder {
+	sb.logger = l
+
+	return sb
+}
+
+// SetErrorLogFunc set a callback for ErrorLog policy instead of logger
+// ex : log via slog
+// ```
+// SetErrorLogFunc(func(err error) { slog.Error("sqlbuilder", "err", err) })
+// ```
+func (sb *SQLBuilder) SetErrorLogFunc(fn func(err error)) *SQLBuilder {
+	sb.errLogFn = fn
+
+	return sb
+}
+
+// handleError panic, log or do nothing by the ErrorPolicy
+func (sb *SQLBuilder) handleError(err error) {
+	switch sb.errPolicy {
+	case ErrorLog:
+		if sb.errLogFn != nil {
+			sb.errLogFn(err)
+		} else if sb.logger != nil {
+			sb.logger.Println(err)
+		} else {
+			errLog.Println(err)
+		}
+	case ErrorCollect:
+	default:
+		panic(err)
+	}
+}
+
+// addError record a error on builder, the Build...() will return it
+func (sb *SQLBuilder) addError(err error, msg string) {
+	sb.errs = append(sb.errs, &BuildError{Err: err, Msg: msg})
+}
+
+// Err return the first error that recorded on builder or nil
+// it include the errors of last build
+func (sb *SQLBuilder) Err() error {
+	if errs := sb.Errors(); len(errs) > 0 {
+		return errs[0]
+	}
+
+	return nil
+}
+
+// Errors return all the errors that recorded on builder
+// it include the errors of last build
+func (sb *SQLBuilder) Errors() []error {
+	errs := make([]error, 0, len(sb.errs)+len(sb.buildErrs))
+
+	return append(append(errs, sb.errs...), sb.buildErrs...)
+}
+
+// addError record a error while building, it is keep on builder until next build
+func (ctx *buildCtx) addError(err error, msg string) {
+	ctx.errs = append(ctx.errs, &BuildError{Err: err, Msg: msg})
+}
@@ -0,0 +1,296 @@
+// Author :		Eric<eehsiao@gmail.com>
+
+package sqlbuilder
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Expr is a node of the sql expression tree
+// the builder keep the nodes and only render them at build time,
+// so the output is always for the current dialect of builder
+// it can be a value of Where(), Set(), Values() ...
+type Expr interface {
+	build(ctx *buildCtx) string
+}
+
+// column is a column or table reference
+type column struct {
+	name string
+}
+
+// literal is a value, inline or bind as placeholder
+type literal struct {
+	v interface{}
+}
+
+// binary is a operator with two operands, ex : `a = 1`
+type binary struct {
+	l Expr
+	o string
+	r Expr
+}
+
+// in is `l IN (...)` with a list of values or a sub query
+type in struct {
+	l    Expr
+	not  bool
+	list []Expr
+	sub  *SQLBuilder
+	bad  bool // the value is not a slice or sub query
+}
+
+// between is `l BETWEEN a AND b`
+type between struct {
+	l   Expr
+	not bool
+	a   Expr
+	b   Expr
+}
+
+// isNull is `l IS NULL`
+type isNull struct {
+	l   Expr
+	not bool
+}
+
+// like is `l LIKE r`, with `ESCAPE` when the pattern is escaped
+// the escaped pattern is `prefix + v + suffix`, the v is escaped by dialect at build time
+type like struct {
+	l       Expr
+	r       Expr
+	escaped bool
+	v       string
+	prefix  string
+	suffix  string
+}
+
+// exists is `EXISTS (sub query)`
+type exists struct {
+	sub *SQLBuilder
+	not bool
+}
+
+// function is a function call, ex : `COUNT(id)`
+type function struct {
+	name string
+	args []Expr
+}
+
+// alias is a expression with alias name, ex : `COUNT(id) AS cnt`
+type alias struct {
+	e    Expr
+	name string
+}
+
+// subquery is a select builder in parentheses, with a alias name if has
+type subquery struct {
+	sb   *SQLBuilder
+	name string
+}
+
+// Col return a column or table reference expression
+// it will be quoted when QuoteIdentifiers(true)
+func Col(name string) Expr {
+	return column{name: name}
+}
+
+// Fn return a function call expression
+// the args can be Expr, ex : Col(), or a value
+// ex :
+// ```
+// Fn("COALESCE", Col("nick"), "guest")
+// ```
+func Fn(name string, args ...interface{}) Expr {
+	es := make([]Expr, 0, len(args))
+	for _, a := range args {
+		es = append(es, valueExpr(a))
+	}
+
+	return function{name: name, args: es}
+}
+
+// Sub return a sub query expression with alias name
+// it can be a source of FromExpr(), JoinExpr() or a column of SelectExpr()
+// the sub query is rendered by the dialect of outer builder,
+// and its args are merged in the right order
+// ex :
+// ```
+// FromExpr(Sub(NewSQLBuilder().Select("id").From("tblA"), "t"))
+// ```
+func Sub(sb *SQLBuilder, name string) Expr {
+	return subquery{sb: sb, name: name}
+}
+
+// As return a expression with alias name
+func As(e Expr, name string) Expr {
+	return alias{e: e, name: name}
+}
+
+// valueExpr wrap a value to Expr
+func valueExpr(v interface{}) Expr {
+	switch val := v.(type) {
+	case Expr:
+		return val
+	case *SQLBuilder:
+		return subquery{sb: val}
+	}
+
+	return literal{v: v}
+}
+
+// compare return the `s o v` expression
+// the `in` / `not in` with a value that not a expression is same as OnIn() / OnNotIn()
+func compare(s string, o string, v interface{}) Expr {
+	if _, ok := v.(Expr); !ok {
+		switch strings.ToUpper(strings.Join(strings.Fields(o), " ")) {
+		case "IN":
+			return inExpr(s, false, v)
+		case "NOT IN":
+			return inExpr(s, true, v)
+		}
+	}
+
+	return binary{l: column{name: s}, o: o, r: valueExpr(v)}
+}
+
+// inExpr return the `in` expression of a slice or sub query
+// the expression value is used as it is, ex : `a IN (SELECT 1)`
+// the other value is a error at build time
+func inExpr(s string, not bool, v interface{}) Expr {
+	e := in{l: column{name: s}, not: not}
+	if val, ok := v.(Expr); ok {
+		o := "IN"
+		if not {
+			o = "NOT IN"
+		}
+		return binary{l: e.l, o: o, r: val}
+	}
+	if sub, ok := v.(*SQLBuilder); ok {
+		e.sub, e.bad = sub, sub == nil
+		return e
+	}
+
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return e
+	}
+	if _, ok := v.([]byte); ok || (rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array) {
+		e.bad = true
+		return e
+	}
+	for i := 0; i < rv.Len(); i++ {
+		e.list = append(e.list, valueExpr(rv.Index(i).Interface()))
+	}
+
+	return e
+}
+
+func (e column) build(ctx *buildCtx) string {
+	return ctx.ident(e.name)
+}
+
+func (e literal) build(ctx *buildCtx) string {
+	return ctx.value(e.v)
+}
+
+func (e binary) build(ctx *buildCtx) string {
+	return e.l.build(ctx) + " " + ctx.escape(e.o) + " " + e.r.build(ctx)
+}
+
+func (e in) build(ctx *buildCtx) string {
+	o := " IN "
+	if e.not {
+		o = " NOT IN "
+	}
+	if e.bad {
+		ctx.addError(ErrInvalidArgument, "the value of in must be a slice or sub query")
+		return ""
+	}
+	if e.sub != nil {
+		return e.l.build(ctx) + o + subquery{sb: e.sub}.build(ctx)
+	}
+
+	// the empty list is always false for `in`, always true for `not in`
+	if len(e.list) == 0 {
+		if e.not {
+			return "1=1"
+		}
+		return "1=0"
+	}
+
+	return e.l.build(ctx) + o + "(" + ctx.exprs(e.list) + ")"
+}
+
+func (e between) build(ctx *buildCtx) string {
+	o := " BETWEEN "
+	if e.not {
+		o = " NOT BETWEEN "
+	}
+
+	return e.l.build(ctx) + o + e.a.build(ctx) + " AND " + e.b.build(ctx)
+}
+
+func (e isNull) build(ctx *buildCtx) string {
+	if e.not {
+		return e.l.build(ctx) + " IS NOT NULL"
+	}
+
+	return e.l.build(ctx) + " IS NULL"
+}
+
+func (e like) build(ctx *buildCtx) string {
+	if !e.escaped {
+		return e.l.build(ctx) + " LIKE " + e.r.build(ctx)
+	}
+
+	str := e.l.build(ctx) + " LIKE " + ctx.value(e.prefix+ctx.escapeLike(e.v)+e.suffix)
+	str += " ESCAPE '" + likeEscape + "'"
+
+	return str
+}
+
+func (e exists) build(ctx *buildCtx) string {
+	if e.not {
+		return "NOT EXISTS " + subquery{sb: e.sub}.build(ctx)
+	}
+
+	return "EXISTS " + subquery{sb: e.sub}.build(ctx)
+}
+
+func (e function) build(ctx *buildCtx) string {
+	args := make([]string, 0, len(e.args))
+	for _, a := range e.args {
+		args = append(args, a.build(ctx))
+	}
+
+	return ctx.escape(e.name) + "(" + strings.Join(args, ",") + ")"
+}
+
+func (e alias) build(ctx *buildCtx) string {
+	return e.e.build(ctx) + " AS " + ctx.ident(e.name)
+}
+
+func (e subquery) build(ctx *buildCtx) string {
+	if e.sb == nil {
+		ctx.addError(ErrInvalidArgument, "nil sub query")
+		return ""
+	}
+	if len(e.sb.errs) > 0 {
+		ctx.errs = append(ctx.errs, e.sb.errs...)
+		return ""
+	}
+
+	str := "(" + e.sb.buildSelect(ctx) + ")"
+	if e.name != "" {
+		str += " " + ctx.ident(e.name)
+	}
+
+	return str
+}
+
+// build make SQLVar as a raw expression
+func (v SQLVar) build(ctx *buildCtx) string {
+	return v.VarS
+}
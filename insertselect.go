@@ -0,0 +1,102 @@
+// Author :		Eric<eehsiao@gmail.com>
+
+package sqlbuilder
+
+import (
+	"strconv"
+	"strings"
+)
+
+// FromSelect set the select as the rows of insert, instead of Values()
+// the fields count must be same as the select columns count when it is known,
+// it can be with Upsert() and Returning(), for the mssql and oracle upsert,
+// the select columns must be named as the fields
+// ex :
+// ```
+// Into("tblA").Fields("a", "b").FromSelect(NewSQLBuilder().Select("a", "b").From("tblB")).BuildInsert()
+// ```
+// is `INSERT INTO tblA (a,b) SELECT a,b FROM tblB`
+func (sb *SQLBuilder) FromSelect(q *SQLBuilder) *SQLBuilder {
+	if q == nil {
+		sb.addError(ErrInvalidArgument, "must be support select for insert")
+		return sb
+	}
+
+	sb.fromSelect = q
+
+	return sb
+}
+
+// IsHasFromSelect is internal function
+func (sb *SQLBuilder) IsHasFromSelect() bool {
+	return sb.fromSelect != nil
+}
+
+// selectCount return the count of select columns, false if it is unknown, ex : `*`
+func (sb *SQLBuilder) selectCount() (int, bool) {
+	for _, e := range sb.selects {
+		switch c := e.(type) {
+		case column:
+			if strings.ContainsAny(c.name, "*,") {
+				return 0, false
+			}
+		case SQLVar:
+			return 0, false
+		}
+	}
+
+	return len(sb.selects), true
+}
+
+// endsWithFrom return true if the last clause of select is `FROM` or `JOIN`
+func (sb *SQLBuilder) endsWithFrom() bool {
+	return !sb.IsHasWheres() && !sb.IsHasGroups() && !sb.IsHasHavings() && !sb.IsHasWindows() &&
+		!sb.IsHasCompounds() && !sb.IsHasOrders() && !sb.IsHasLimit() && !sb.IsHasOffset()
+}
+
+// insertSelect render the select of insert, the `WITH` is before the select when with is true
+func (sb *SQLBuilder) insertSelect(ctx *buildCtx, with bool) (string, bool) {
+	q := sb.fromSelect
+	if len(q.errs) > 0 {
+		ctx.errs = append(ctx.errs, q.errs...)
+		return "", false
+	}
+	if n, ok := q.selectCount(); ok && n != len(sb.fields) {
+		ctx.addError(ErrFieldValueCount, "select columns count "+strconv.Itoa(n)+" not equal fileds count")
+		return "", false
+	}
+
+	sql := ""
+	if with {
+		var ok bool
+		if sql, ok = sb.with(ctx, "SELECT"); !ok {
+			return "", false
+		}
+	}
+
+	str := q.buildSelect(ctx)
+	if str == "" {
+		return "", false
+	}
+	// SQLite parse the `ON` of `ON CONFLICT` as a join constraint, if the select is end with `FROM`
+	if sb.IsHasUpsert() && ctx.dialect.UpsertStyle() == UpsertOnConflict && q.endsWithFrom() {
+		str += " WHERE true"
+	}
+
+	return sql + str, true
+}
+
+// insertRows render the rows of insert, ` VALUES (...),(...)` or ` SELECT ...`
+func (sb *SQLBuilder) insertRows(ctx *buildCtx, rows [][]interface{}, with bool) (string, bool) {
+	if sb.IsHasFromSelect() {
+		str, ok := sb.insertSelect(ctx, with)
+		return " " + str, ok
+	}
+
+	strs := make([]string, 0, len(rows))
+	for _, vs := range rows {
+		strs = append(strs, ctx.row(vs))
+	}
+
+	return " VALUES " + strings.Join(strs, ","), true
+}
@@ -0,0 +1,151 @@
+// Author :		Eric<eehsiao@gmail.com>
+
+package sqlbuilder
+
+import (
+	"strings"
+)
+
+// likeEscape is the escape char of LIKE pattern
+// the backslash is a string escape in mysql, but `!` is plain in every dialect,
+// so the same `ESCAPE '!'` works for all of them
+const likeEscape = "!"
+
+var (
+	likeReplacer = strings.NewReplacer(
+		likeEscape, likeEscape+likeEscape,
+		"%", likeEscape+"%",
+		"_", likeEscape+"_",
+	)
+	// mssql has the `[...]` char class wildcard also
+	tsqlLikeReplacer = strings.NewReplacer(
+		likeEscape, likeEscape+likeEscape,
+		"%", likeEscape+"%",
+		"_", likeEscape+"_",
+		"[", likeEscape+"[",
+	)
+)
+
+// EscapeLike escape the LIKE wildcards `%`, `_` of s
+// the result need to use with `ESCAPE '!'`, the `[` of mssql is not escaped,
+// OnContains() and others escape by dialect, they are better choice
+func EscapeLike(s string) string {
+	return likeReplacer.Replace(s)
+}
+
+// escapeLike escape the LIKE wildcards of s by dialect
+func (ctx *buildCtx) escapeLike(s string) string {
+	if isTSQL(ctx.dialect) {
+		return tsqlLikeReplacer.Replace(s)
+	}
+
+	return likeReplacer.Replace(s)
+}
+
+// OnBetween return a `between` sub condition
+func OnBetween(s string, a interface{}, b interface{}) SubCond {
+	return SubCond{c: true, e: between{l: Col(s), a: valueExpr(a), b: valueExpr(b)}}
+}
+
+// OnNotBetween return a `not between` sub condition
+func OnNotBetween(s string, a interface{}, b interface{}) SubCond {
+	return SubCond{c: true, e: between{l: Col(s), not: true, a: valueExpr(a), b: valueExpr(b)}}
+}
+
+// OnIsNull return a `is null` sub condition
+func OnIsNull(s string) SubCond {
+	return SubCond{c: true, e: isNull{l: Col(s)}}
+}
+
+// OnIsNotNull return a `is not null` sub condition
+func OnIsNotNull(s string) SubCond {
+	return SubCond{c: true, e: isNull{l: Col(s), not: true}}
+}
+
+// OnLike return a `like` sub condition
+// the pattern is used as it is, the `%` and `_` are wildcards
+func OnLike(s string, pattern string) SubCond {
+	return SubCond{c: true, e: like{l: Col(s), r: valueExpr(pattern)}}
+}
+
+// OnContains return a `like '%v%'` sub condition
+// the wildcards in v are escaped by dialect, so they are not wildcards
+func OnContains(s string, v string) SubCond {
+	return SubCond{c: true, e: like{l: Col(s), escaped: true, v: v, prefix: "%", suffix: "%"}}
+}
+
+// OnStartsWith return a `like 'v%'` sub condition
+// the wildcards in v are escaped by dialect, so they are not wildcards
+func OnStartsWith(s string, v string) SubCond {
+	return SubCond{c: true, e: like{l: Col(s), escaped: true, v: v, suffix: "%"}}
+}
+
+// OnEndsWith return a `like '%v'` sub condition
+// the wildcards in v are escaped by dialect, so they are not wildcards
+func OnEndsWith(s string, v string) SubCond {
+	return SubCond{c: true, e: like{l: Col(s), escaped: true, v: v, prefix: "%"}}
+}
+
+func (sb *SQLBuilder) whereOn(s string, con SubCond) *SQLBuilder {
+	if s == "" {
+		sb.addError(ErrMissingCondition, "must be support conditions")
+		return sb
+	}
+
+	sb.wheres = append(sb.wheres, con)
+
+	return sb
+}
+
+// WhereBetween add a `between` condition, if this not first time use, its will be `and` condition
+// ex :
+// ```
+// WhereBetween('fieldA', 1, 10)
+// ```
+func (sb *SQLBuilder) WhereBetween(s string, a interface{}, b interface{}) *SQLBuilder {
+	return sb.whereOn(s, OnBetween(s, a, b))
+}
+
+// WhereNotBetween add a `not between` condition
+func (sb *SQLBuilder) WhereNotBetween(s string, a interface{}, b interface{}) *SQLBuilder {
+	return sb.whereOn(s, OnNotBetween(s, a, b))
+}
+
+// WhereIsNull add a `is null` condition
+func (sb *SQLBuilder) WhereIsNull(s string) *SQLBuilder {
+	return sb.whereOn(s, OnIsNull(s))
+}
+
+// WhereIsNotNull add a `is not null` condition
+func (sb *SQLBuilder) WhereIsNotNull(s string) *SQLBuilder {
+	return sb.whereOn(s, OnIsNotNull(s))
+}
+
+// WhereLike add a `like` condition, the pattern is used as it is
+// ex :
+// ```
+// WhereLike('fieldA', 'abc%')
+// ```
+func (sb *SQLBuilder) WhereLike(s string, pattern string) *SQLBuilder {
+	return sb.whereOn(s, OnLike(s, pattern))
+}
+
+// WhereContains add a `like '%v%'` condition, the wildcards in v are escaped
+// ex :
+// ```
+// WhereContains('fieldA', '50%')
+// ```
+// is `fieldA LIKE '%50!%%' ESCAPE '!'`
+func (sb *SQLBuilder) WhereContains(s string, v string) *SQLBuilder {
+	return sb.whereOn(s, OnContains(s, v))
+}
+
+// WhereStartsWith add a `like 'v%'` condition, the wildcards in v are escaped
+func (sb *SQLBuilder) WhereStartsWith(s string, v string) *SQLBuilder {
+	return sb.whereOn(s, OnStartsWith(s, v))
+}
+
+// WhereEndsWith add a `like '%v'` condition, the wildcards in v are escaped
+func (sb *SQLBuilder) WhereEndsWith(s string, v string) *SQLBuilder {
+	return sb.whereOn(s, OnEndsWith(s, v))
+}
@@ -0,0 +1,328 @@
+// Author :		Eric<eehsiao@gmail.com>
+
+package sqlbuilder
+
+import (
+	"strings"
+)
+
+// the branch kinds of merge
+const (
+	whenMatched = iota
+	whenNotMatched
+	whenNotMatchedBySource
+)
+
+// merge is the `MERGE INTO target USING source ON ...` statement
+type merge struct {
+	src   Expr
+	on    []SubCond
+	whens []mergeWhen
+}
+
+// mergeWhen is one `WHEN ... THEN ...` branch
+type mergeWhen struct {
+	kind   int
+	action string // `UPDATE`, `DELETE` or `INSERT`
+	conds  []SubCond
+	sets   []Set
+	fields []string
+	values []interface{}
+}
+
+// valuesSource is the rows source of merge, with alias and columns
+type valuesSource struct {
+	name string
+	cols []string
+	rows [][]interface{}
+}
+
+func (e valuesSource) build(ctx *buildCtx) string {
+	return "(" + ctx.selectRows(e.cols, e.rows) + ") " + ctx.ident(e.name)
+}
+
+func (sb *SQLBuilder) mergeOf() *merge {
+	if sb.merge == nil {
+		sb.merge = &merge{}
+	}
+
+	return sb.merge
+}
+
+// UsingTable set the table source of merge, the target is Into(), ex : `Into("tblA t")`
+// ex :
+// ```
+// Into("tblA t").UsingTable("tblB", "s").MergeOn(On("t.id", "=", Col("s.id")))
+// ```
+func (sb *SQLBuilder) UsingTable(table string, alias string) *SQLBuilder {
+	if table == "" || alias == "" {
+		sb.addError(ErrMissingTable, "must be support source table and alias")
+		return sb
+	}
+
+	sb.mergeOf().src = Col(table + " " + alias)
+
+	return sb
+}
+
+// UsingQuery set the sub query source of merge
+func (sb *SQLBuilder) UsingQuery(q *SQLBuilder, alias string) *SQLBuilder {
+	if q == nil || alias == "" {
+		sb.addError(ErrMissingTable, "must be support source query and alias")
+		return sb
+	}
+
+	sb.mergeOf().src = Sub(q, alias)
+
+	return sb
+}
+
+// UsingValues set the rows source of merge, the values are bind or escaped
+// ex :
+// ```
+// UsingValues("s", []string{"id", "name"}, []interface{}{1, "a"}, []interface{}{2, "b"})
+// ```
+func (sb *SQLBuilder) UsingValues(alias string, cols []string, rows ...[]interface{}) *SQLBuilder {
+	if alias == "" || len(cols) == 0 {
+		sb.addError(ErrMissingFields, "must be support source alias and columns")
+		return sb
+	}
+	if len(rows) == 0 {
+		sb.addError(ErrMissingValues, "must be support source rows")
+		return sb
+	}
+	for _, r := range rows {
+		if len(r) != len(cols) {
+			sb.addError(ErrFieldValueCount, "source row values count not equal columns count")
+			return sb
+		}
+	}
+
+	sb.mergeOf().src = valuesSource{name: alias, cols: cols, rows: rows}
+
+	return sb
+}
+
+// MergeOn set the match condition of merge
+func (sb *SQLBuilder) MergeOn(on ...SubCond) *SQLBuilder {
+	if len(on) == 0 {
+		sb.addError(ErrMissingCondition, "without condition")
+		return sb
+	}
+
+	sb.mergeOf().on = append(sb.mergeOf().on, on...)
+
+	return sb
+}
+
+func (sb *SQLBuilder) when(w mergeWhen) *SQLBuilder {
+	sb.mergeOf().whens = append(sb.mergeOf().whens, w)
+
+	return sb
+}
+
+// WhenMatchedUpdate add `WHEN MATCHED [AND conds] THEN UPDATE SET ...`
+func (sb *SQLBuilder) WhenMatchedUpdate(sets []Set, conds ...SubCond) *SQLBuilder {
+	if len(sets) == 0 {
+		sb.addError(ErrMissingValues, "must be support set fileds : values")
+		return sb
+	}
+
+	return sb.when(mergeWhen{kind: whenMatched, action: "UPDATE", conds: conds, sets: sets})
+}
+
+// WhenMatchedDelete add `WHEN MATCHED [AND conds] THEN DELETE`
+func (sb *SQLBuilder) WhenMatchedDelete(conds ...SubCond) *SQLBuilder {
+	return sb.when(mergeWhen{kind: whenMatched, action: "DELETE", conds: conds})
+}
+
+// WhenNotMatchedInsert add `WHEN NOT MATCHED [AND conds] THEN INSERT (fields) VALUES (values)`
+// the values can be the source columns, ex : Col("s.id")
+func (sb *SQLBuilder) WhenNotMatchedInsert(fields []string, values []interface{}, conds ...SubCond) *SQLBuilder {
+	if len(fields) == 0 {
+		sb.addError(ErrMissingFields, "must be support fileds")
+		return sb
+	}
+	if len(fields) != len(values) {
+		sb.addError(ErrFieldValueCount, "values count not equal fileds count")
+		return sb
+	}
+
+	return sb.when(mergeWhen{kind: whenNotMatched, action: "INSERT", conds: conds, fields: fields, values: values})
+}
+
+// WhenNotMatchedBySourceUpdate add `WHEN NOT MATCHED BY SOURCE [AND conds] THEN UPDATE SET ...`
+// only for mssql
+func (sb *SQLBuilder) WhenNotMatchedBySourceUpdate(sets []Set, conds ...SubCond) *SQLBuilder {
+	if len(sets) == 0 {
+		sb.addError(ErrMissingValues, "must be support set fileds : values")
+		return sb
+	}
+
+	return sb.when(mergeWhen{kind: whenNotMatchedBySource, action: "UPDATE", conds: conds, sets: sets})
+}
+
+// WhenNotMatchedBySourceDelete add `WHEN NOT MATCHED BY SOURCE [AND conds] THEN DELETE`
+// only for mssql
+func (sb *SQLBuilder) WhenNotMatchedBySourceDelete(conds ...SubCond) *SQLBuilder {
+	return sb.when(mergeWhen{kind: whenNotMatchedBySource, action: "DELETE", conds: conds})
+}
+
+// BuildMergeSQL do build the `merge` SQL string
+// only for the dialect that MergeStyle() is not MergeNone, ex : mssql and oracle
+func (sb *SQLBuilder) BuildMergeSQL() *SQLBuilder {
+	return sb.buildSQL(sb.buildMerge)
+}
+
+// BuildMerge do build the `merge` SQL string, return with args and error
+func (sb *SQLBuilder) BuildMerge() (string, []interface{}, error) {
+	return sb.build(sb.buildMerge)
+}
+
+func (sb *SQLBuilder) buildMerge(ctx *buildCtx) string {
+	m := sb.merge
+	switch {
+	case ctx.dialect.MergeStyle() == MergeNone:
+		ctx.addError(ErrUnsupported, "merge not support by "+ctx.dialect.Name())
+		return ""
+	case !(sb.IsHasInto() || sb.IsHasTbName()):
+		ctx.addError(ErrMissingTable, "Without merge table or default TbName")
+		return ""
+	case m == nil || m.src == nil:
+		ctx.addError(ErrMissingTable, "Without merge source")
+		return ""
+	case len(m.on) == 0:
+		ctx.addError(ErrMissingCondition, "Without merge on condition")
+		return ""
+	case len(m.whens) == 0:
+		ctx.addError(ErrMissingCondition, "Without merge when branch")
+		return ""
+	}
+
+	sql, ok := sb.with(ctx, "MERGE")
+	if !ok {
+		return ""
+	}
+
+	sql += "MERGE INTO " + sb.intoTable(ctx) + " USING " + m.src.build(ctx) + " ON (" + ctx.conds(m.on) + ")"
+
+	var whens string
+	if ctx.dialect.MergeStyle() == MergeWhere {
+		whens, ok = m.whereWhens(ctx)
+	} else {
+		whens, ok = m.andWhens(ctx)
+	}
+	if !ok {
+		return ""
+	}
+	sql += whens
+
+	tail, ok := sb.mergeTail(ctx)
+	if !ok {
+		return ""
+	}
+
+	return sql + tail
+}
+
+// mergeTail render the end of `MERGE`, the output or returning clause
+// and the semicolon that mssql must end the `MERGE` with
+func (sb *SQLBuilder) mergeTail(ctx *buildCtx) (string, bool) {
+	returning, ok := sb.returning(ctx, "MERGE", 0)
+	if !ok {
+		return "", false
+	}
+	tail := sb.output(ctx, "MERGE") + returning
+
+	if isTSQL(ctx.dialect) {
+		tail += ";"
+	}
+
+	return tail, true
+}
+
+// andWhens render the branches with `AND` conditions
+func (m *merge) andWhens(ctx *buildCtx) (string, bool) {
+	sql := ""
+	for _, w := range m.whens {
+		switch w.kind {
+		case whenMatched:
+			sql += " WHEN MATCHED"
+		case whenNotMatched:
+			sql += " WHEN NOT MATCHED"
+		case whenNotMatchedBySource:
+			sql += " WHEN NOT MATCHED BY SOURCE"
+		}
+		if len(w.conds) > 0 {
+			sql += " AND " + ctx.groupConds(w.conds)
+		}
+		sql += " THEN " + w.then(ctx)
+	}
+
+	return sql, true
+}
+
+// whereWhens render the branches as oracle, the conditions are `WHERE`,
+// the matched delete is with the matched update, and without the by source
+func (m *merge) whereWhens(ctx *buildCtx) (string, bool) {
+	var upd, del, ins *mergeWhen
+	for i, w := range m.whens {
+		var p **mergeWhen
+		switch {
+		case w.kind == whenMatched && w.action == "UPDATE":
+			p = &upd
+		case w.kind == whenMatched && w.action == "DELETE":
+			p = &del
+		case w.kind == whenNotMatched:
+			p = &ins
+		default:
+			ctx.addError(ErrUnsupported, "when not matched by source not support by "+ctx.dialect.Name())
+			return "", false
+		}
+		if *p != nil {
+			ctx.addError(ErrUnsupported, "multiple same when branches not support by "+ctx.dialect.Name())
+			return "", false
+		}
+		*p = &m.whens[i]
+	}
+	if del != nil && upd == nil {
+		ctx.addError(ErrUnsupported, "when matched delete without update not support by "+ctx.dialect.Name())
+		return "", false
+	}
+
+	sql := ""
+	if upd != nil {
+		sql += " WHEN MATCHED THEN " + upd.then(ctx)
+		if len(upd.conds) > 0 {
+			sql += " WHERE " + ctx.conds(upd.conds)
+		}
+		if del != nil {
+			// the `DELETE` must have `WHERE`
+			cond := "1=1"
+			if len(del.conds) > 0 {
+				cond = ctx.conds(del.conds)
+			}
+			sql += " DELETE WHERE " + cond
+		}
+	}
+	if ins != nil {
+		sql += " WHEN NOT MATCHED THEN " + ins.then(ctx)
+		if len(ins.conds) > 0 {
+			sql += " WHERE " + ctx.conds(ins.conds)
+		}
+	}
+
+	return sql, true
+}
+
+// then render the action of branch
+func (w mergeWhen) then(ctx *buildCtx) string {
+	switch w.action {
+	case "UPDATE":
+		return "UPDATE SET " + strings.Join(ctx.sets(w.sets, ""), ",")
+	case "INSERT":
+		return "INSERT (" + ctx.idents(w.fields) + ") " + "VALUES " + ctx.row(w.values)
+	}
+
+	return w.action
+}
@@ -0,0 +1,140 @@
+// Author :		Eric<eehsiao@gmail.com>
+
+package sqlbuilder
+
+import (
+	"strings"
+)
+
+// IsMultiTable is internal function
+// that the update or delete is with joins or more than one from tables
+func (sb *SQLBuilder) IsMultiTable() bool {
+	return sb.IsHasJoins() || len(sb.froms) > 1
+}
+
+// targetAlias return the alias of target table, or the table name if it is without alias
+func (sb *SQLBuilder) targetAlias() string {
+	name := sb.tbName
+	if sb.IsHasFroms() {
+		if c, ok := sb.froms[0].(column); ok {
+			name = c.name
+		}
+	}
+	if ws := strings.Fields(name); len(ws) > 1 {
+		return ws[len(ws)-1]
+	}
+
+	return name
+}
+
+// target render the target table of multi table update or delete
+func (sb *SQLBuilder) target(ctx *buildCtx) string {
+	if sb.IsHasFroms() {
+		return sb.froms[0].build(ctx)
+	}
+
+	return ctx.ident(sb.tbName)
+}
+
+// joinSources render the joins and the other from tables, after the target table
+func (sb *SQLBuilder) joinSources(ctx *buildCtx) string {
+	str := ""
+	if sb.IsHasJoins() {
+		str += " " + ctx.joins(sb.joins)
+	}
+	if len(sb.froms) > 1 {
+		str += "," + ctx.exprs(sb.froms[1:])
+	}
+
+	return str
+}
+
+// fromSources render the joins and the other from tables as a table list,
+// the join conditions are return to be the where conditions
+// only the inner join can be a table list
+func (sb *SQLBuilder) fromSources(ctx *buildCtx) (string, [][]SubCond, bool) {
+	tables := make([]string, 0, len(sb.joins)+len(sb.froms))
+	ons := make([][]SubCond, 0, len(sb.joins)+1)
+	for _, j := range sb.joins {
+		if j.p != "" && j.p != "INNER " {
+			ctx.addError(ErrUnsupported, strings.ToLower(j.p)+"join of update or delete not support by "+ctx.dialect.Name())
+			return "", nil, false
+		}
+		tables = append(tables, j.t.build(ctx))
+		if len(j.on) > 0 {
+			ons = append(ons, j.on)
+		}
+	}
+	if len(sb.froms) > 1 {
+		tables = append(tables, ctx.exprs(sb.froms[1:]))
+	}
+
+	return strings.Join(tables, ","), ons, true
+}
+
+// multiWhere render the `WHERE` with the join conditions and the where conditions
+func (sb *SQLBuilder) multiWhere(ctx *buildCtx, ons [][]SubCond) string {
+	if sb.IsHasWheres() {
+		ons = append(ons, sb.wheres)
+	}
+	switch len(ons) {
+	case 0:
+		return ""
+	case 1:
+		return " WHERE " + ctx.conds(ons[0])
+	}
+
+	strs := make([]string, 0, len(ons))
+	for _, on := range ons {
+		strs = append(strs, ctx.groupConds(on))
+	}
+
+	return " WHERE " + strings.Join(strs, " AND ")
+}
+
+// multiUpdate render the update with joins or more than one from tables
+func (sb *SQLBuilder) multiUpdate(ctx *buildCtx) (string, bool) {
+	// the sets are rendered in text order, the args are bound as same as the sql
+	sets := func() string { return " SET " + strings.Join(ctx.sets(sb.sets, ""), ",") }
+
+	switch ctx.dialect.MultiTableStyle() {
+	case MultiTableJoin:
+		sql := "UPDATE " + sb.target(ctx) + sb.joinSources(ctx)
+		sql += sets()
+		return sql + sb.multiWhere(ctx, nil), true
+	case MultiTableFromJoin:
+		sql := "UPDATE " + ctx.ident(sb.targetAlias())
+		sql += sets() + sb.output(ctx, "UPDATE")
+		return sql + " FROM " + sb.target(ctx) + sb.joinSources(ctx) + sb.multiWhere(ctx, nil), true
+	case MultiTableUsing, MultiTableUpdateFrom:
+		sql := "UPDATE " + sb.target(ctx)
+		sql += sets()
+		from, ons, ok := sb.fromSources(ctx)
+		if !ok {
+			return "", false
+		}
+		return sql + " FROM " + from + sb.multiWhere(ctx, ons), true
+	}
+
+	ctx.addError(ErrUnsupported, "update with join not support by "+ctx.dialect.Name())
+	return "", false
+}
+
+// multiDelete render the delete with joins or more than one from tables
+func (sb *SQLBuilder) multiDelete(ctx *buildCtx) (string, bool) {
+	switch ctx.dialect.MultiTableStyle() {
+	case MultiTableJoin, MultiTableFromJoin:
+		sql := "DELETE " + ctx.ident(sb.targetAlias()) + sb.output(ctx, "DELETE")
+		return sql + " FROM " + sb.target(ctx) + sb.joinSources(ctx) + sb.multiWhere(ctx, nil), true
+	case MultiTableUsing:
+		sql := "DELETE FROM " + sb.target(ctx)
+		from, ons, ok := sb.fromSources(ctx)
+		if !ok {
+			return "", false
+		}
+		return sql + " USING " + from + sb.multiWhere(ctx, ons), true
+	}
+
+	ctx.addError(ErrUnsupported, "delete with join not support by "+ctx.dialect.Name())
+	return "", false
+}
@@ -0,0 +1,124 @@
+// Author :		Eric<eehsiao@gmail.com>
+
+package sqlbuilder
+
+import (
+	"strings"
+	"unicode"
+)
+
+// QuoteIdent quote the table or column name by the dialect
+// it understand the `schema.table.column`, `table alias` and `table AS alias` forms,
+// the part that already quoted or `*` will keep as it is
+// the expression, ex : `count(*)`, is not a identifier and only be escaped
+func (sb *SQLBuilder) QuoteIdent(s string) string {
+	return quoteIdent(sb.dialect, s)
+}
+
+func quoteIdent(d Dialect, s string) string {
+	words, ok := splitOutsideQuote(s, func(c byte) bool { return c == ' ' || c == '\t' || c == '\n' })
+	if !ok {
+		return d.EscapeStr(s)
+	}
+	ws := make([]string, 0, len(words))
+	for _, w := range words {
+		if w != "" {
+			ws = append(ws, w)
+		}
+	}
+
+	path, name, as := "", "", ""
+	switch {
+	case len(ws) == 1:
+		path = ws[0]
+	case len(ws) == 2:
+		path, name = ws[0], ws[1]
+	case len(ws) == 3 && strings.EqualFold(ws[1], "AS"):
+		path, as, name = ws[0], ws[1], ws[2]
+	default:
+		return d.EscapeStr(s)
+	}
+
+	parts, ok := splitOutsideQuote(path, func(c byte) bool { return c == '.' })
+	if !ok {
+		return d.EscapeStr(s)
+	}
+	for i, p := range parts {
+		if p == "*" && i == len(parts)-1 {
+			continue
+		}
+		q, ok := quotePart(d, p)
+		if !ok {
+			return d.EscapeStr(s)
+		}
+		parts[i] = q
+	}
+	str := strings.Join(parts, ".")
+
+	if name != "" {
+		q, ok := quotePart(d, name)
+		if !ok {
+			return d.EscapeStr(s)
+		}
+		if as != "" {
+			str += " " + as
+		}
+		str += " " + q
+	}
+
+	return str
+}
+
+// quotePart quote one part of identifier, false if it is not a identifier
+func quotePart(d Dialect, p string) (string, bool) {
+	if isQuoted(p) {
+		return p, true
+	}
+	if p == "" || unicode.IsDigit([]rune(p)[0]) {
+		return "", false
+	}
+	for _, r := range p {
+		if !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '$' || r == '#') {
+			return "", false
+		}
+	}
+
+	return d.QuoteIdent(p), true
+}
+
+func isQuoted(p string) bool {
+	if len(p) < 2 {
+		return false
+	}
+	l, r := p[0], p[len(p)-1]
+
+	return (l == '"' && r == '"') || (l == '`' && r == '`') || (l == '[' && r == ']')
+}
+
+// splitOutsideQuote split s by the separator that not inside the quotes
+// false if there is a unclosed quote
+func splitOutsideQuote(s string, isSep func(c byte) bool) (parts []string, ok bool) {
+	var quote byte
+	start := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '`':
+			quote = c
+		case c == '[':
+			quote = ']'
+		case isSep(c):
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	if quote != 0 {
+		return nil, false
+	}
+
+	return append(parts, s[start:]), true
+}
@@ -0,0 +1,233 @@
+// Author :		Eric<eehsiao@gmail.com>
+
+package sqlbuilder
+
+import (
+	"fmt"
+	"strings"
+)
+
+func (sb *SQLBuilder) newBuildCtx() *buildCtx {
+	return &buildCtx{
+		dialect:       sb.dialect,
+		parameterized: sb.parameterized,
+		quoteIdent:    sb.quoteIdent,
+		args:          make([]interface{}, 0),
+	}
+}
+
+func (sb *SQLBuilder) setBuilded(ctx *buildCtx, sql string) {
+	sb.buildedStr = sql
+	sb.buildedArgs = ctx.args
+}
+
+func (ctx *buildCtx) escape(s string) string {
+	return ctx.dialect.EscapeStr(s)
+}
+
+// ident render the table or column name
+// its only be quoted when QuoteIdentifiers(true)
+func (ctx *buildCtx) ident(s string) string {
+	if ctx.quoteIdent {
+		return quoteIdent(ctx.dialect, s)
+	}
+
+	return ctx.escape(s)
+}
+
+func (ctx *buildCtx) idents(s []string) string {
+	strs := make([]string, 0, len(s))
+	for _, v := range s {
+		strs = append(strs, ctx.ident(v))
+	}
+
+	return strings.Join(strs, ",")
+}
+
+// exprs render the expressions that join with `,`
+func (ctx *buildCtx) exprs(es []Expr) string {
+	strs := make([]string, 0, len(es))
+	for _, e := range es {
+		strs = append(strs, e.build(ctx))
+	}
+
+	return strings.Join(strs, ",")
+}
+
+// value render a value to sql, or bind it as a placeholder arg
+// Expr, ex : SQLVar, and nil are always inline
+func (ctx *buildCtx) value(v interface{}) string {
+	switch val := v.(type) {
+	case Expr:
+		return val.build(ctx)
+	case *SQLBuilder:
+		return subquery{sb: val}.build(ctx)
+	case nil:
+		return "NULL"
+	}
+
+	if ctx.parameterized {
+		ctx.args = append(ctx.args, v)
+		return ctx.dialect.Placeholder(len(ctx.args))
+	}
+
+	switch val := v.(type) {
+	case string:
+		return "'" + ctx.escape(val) + "'"
+	case bool:
+		return ctx.dialect.BoolLiteral(val)
+	}
+
+	return fmt.Sprintf("%v", v)
+}
+
+// cond render one condition without the and/or prefix
+func (ctx *buildCtx) cond(con SubCond) string {
+	if con.op != "" {
+		return ctx.group(con)
+	}
+	if con.e == nil {
+		ctx.addError(ErrMissingCondition, "empty condition")
+		return ""
+	}
+
+	return con.e.build(ctx)
+}
+
+// group render a group of conditions with parentheses
+func (ctx *buildCtx) group(con SubCond) string {
+	if len(con.subs) == 0 {
+		ctx.addError(ErrMissingCondition, "empty condition group")
+		return ""
+	}
+	if con.not && len(con.subs) == 1 && con.subs[0].op != "" && !con.subs[0].not {
+		return "NOT " + ctx.group(con.subs[0])
+	}
+
+	strs := make([]string, 0, len(con.subs))
+	for _, sub := range con.subs {
+		strs = append(strs, ctx.cond(sub))
+	}
+	str := "(" + strings.Join(strs, " "+con.op+" ") + ")"
+	if con.not {
+		str = "NOT " + str
+	}
+
+	return str
+}
+
+// conds render the conditions that join with and/or
+func (ctx *buildCtx) conds(cons []SubCond) string {
+	if others, seeks := splitSeeks(cons); len(seeks) > 0 && len(others) > 0 && hasOr(cons) {
+		return "(" + ctx.conds(others) + ") AND " + ctx.conds(seeks)
+	}
+
+	str := ""
+	for i, con := range cons {
+		if i > 0 {
+			if con.c {
+				str += " AND "
+			} else {
+				str += " OR "
+			}
+		}
+		str += ctx.cond(con)
+	}
+
+	return str
+}
+
+// groupConds render the conditions, with parentheses when more than one
+func (ctx *buildCtx) groupConds(cons []SubCond) string {
+	if len(cons) == 1 {
+		return ctx.cond(cons[0])
+	}
+
+	return "(" + ctx.conds(cons) + ")"
+}
+
+// row render one row of insert values
+func (ctx *buildCtx) row(vs []interface{}) string {
+	vals := make([]string, 0, len(vs))
+	for _, v := range vs {
+		vals = append(vals, ctx.value(v))
+	}
+
+	return "(" + strings.Join(vals, ",") + ")"
+}
+
+// selectRows render the rows as `SELECT ? AS a,? AS b [FROM dual] UNION ALL ...`
+// it is the source of `MERGE`, that work for the dialect without `VALUES` table
+func (ctx *buildCtx) selectRows(cols []string, rows [][]interface{}) string {
+	from := ""
+	if ctx.dialect.DualTable() != "" {
+		from = " FROM " + ctx.dialect.DualTable()
+	}
+
+	strs := make([]string, 0, len(rows))
+	for _, vs := range rows {
+		vals := make([]string, 0, len(vs))
+		for i, v := range vs {
+			vals = append(vals, ctx.value(v)+" AS "+ctx.ident(cols[i]))
+		}
+		strs = append(strs, "SELECT "+strings.Join(vals, ",")+from)
+	}
+
+	return strings.Join(strs, " UNION ALL ")
+}
+
+// sets render the `k=v` of update sets, the k is with prefix, ex : `t.`
+func (ctx *buildCtx) sets(ss []Set, prefix string) []string {
+	strs := make([]string, 0, len(ss))
+	for _, set := range ss {
+		strs = append(strs, prefix+ctx.ident(set.K)+"="+ctx.value(set.V))
+	}
+
+	return strs
+}
+
+// orders render the order by columns
+func (ctx *buildCtx) orders(os []order) string {
+	strs := make([]string, 0, len(os))
+	for _, o := range os {
+		if o.desc {
+			strs = append(strs, o.e.build(ctx)+" DESC")
+		} else {
+			strs = append(strs, o.e.build(ctx)+" ASC")
+		}
+	}
+
+	return strings.Join(strs, ",")
+}
+
+// joins render the join clauses
+func (ctx *buildCtx) joins(js []join) string {
+	strs := make([]string, 0, len(js))
+	for _, j := range js {
+		str := j.p + "JOIN " + j.t.build(ctx)
+		if len(j.on) > 0 {
+			str += " ON " + ctx.conds(j.on)
+		}
+		strs = append(strs, str)
+	}
+
+	return strings.Join(strs, " ")
+}
+
+// table return the table for update or delete
+func (sb *SQLBuilder) table(ctx *buildCtx) string {
+	if sb.IsHasOneFroms() {
+		return sb.froms[0].build(ctx)
+	}
+
+	return ctx.ident(sb.tbName)
+}
+
+// intoTable return the table for insert
+func (sb *SQLBuilder) intoTable(ctx *buildCtx) string {
+	if sb.IsHasInto() {
+		return ctx.ident(sb.into)
+	}
+
+	return ctx.ident(sb.tbName)
+}
@@ -0,0 +1,88 @@
+// Author :		Eric<eehsiao@gmail.com>
+
+package sqlbuilder
+
+import (
+	"strings"
+)
+
+// Returning set the columns that return by insert, update, delete or merge
+// it is rendered by the ReturningStyle() of dialect :
+// `RETURNING cols` for postgresql, SQLite and mariadb (insert and delete),
+// `OUTPUT INSERTED.col` or `OUTPUT DELETED.col` for mssql, the col with `.` is kept as it is,
+// `RETURNING cols INTO :n` for oracle, the out args are not in BuildedArgs(),
+// they need to be append after, ex : `sql.Out{Dest: &id}`
+// ex :
+// ```
+// Into("tblA").Fields("a").Values(1).Returning("id").BuildInsert()
+// ```
+func (sb *SQLBuilder) Returning(cols ...string) *SQLBuilder {
+	if len(cols) == 0 {
+		sb.addError(ErrMissingFields, "must be support returning fileds")
+		return sb
+	}
+
+	sb.returnings = append(sb.returnings, cols...)
+
+	return sb
+}
+
+// IsHasReturning is internal function
+func (sb *SQLBuilder) IsHasReturning() bool {
+	return len(sb.returnings) > 0
+}
+
+// output render the mssql `OUTPUT` clause of stmt, it is empty for other dialects
+func (sb *SQLBuilder) output(ctx *buildCtx, stmt string) string {
+	if !sb.IsHasReturning() || ctx.dialect.ReturningStyle() != ReturningOutput {
+		return ""
+	}
+
+	prefix := "INSERTED."
+	if stmt == "DELETE" {
+		prefix = "DELETED."
+	}
+	strs := make([]string, 0, len(sb.returnings))
+	for _, c := range sb.returnings {
+		if strings.Contains(c, ".") {
+			strs = append(strs, ctx.ident(c))
+		} else {
+			strs = append(strs, prefix+ctx.ident(c))
+		}
+	}
+
+	return " OUTPUT " + strings.Join(strs, ",")
+}
+
+// returning render the `RETURNING` clause at the end of stmt
+// it must be rendered at last, the oracle out placeholders are after all the args
+// the rows is the count of insert rows, it is 0 for other stmt
+func (sb *SQLBuilder) returning(ctx *buildCtx, stmt string, rows int) (string, bool) {
+	if !sb.IsHasReturning() {
+		return "", true
+	}
+
+	switch ctx.dialect.ReturningStyle() {
+	case ReturningOutput:
+		return "", true
+	case ReturningClause:
+		return " RETURNING " + ctx.idents(sb.returnings), true
+	case ReturningInsertDelete:
+		// mariadb only return the single table delete
+		if stmt == "INSERT" || (stmt == "DELETE" && !sb.IsMultiTable()) {
+			return " RETURNING " + ctx.idents(sb.returnings), true
+		}
+	case ReturningInto:
+		// oracle only return into the one row insert, not the insert select or bulk insert
+		if stmt != "MERGE" && !(stmt == "INSERT" && (sb.IsHasFromSelect() || rows > 1)) {
+			ps := make([]string, 0, len(sb.returnings))
+			for i := range sb.returnings {
+				ps = append(ps, ctx.dialect.Placeholder(len(ctx.args)+i+1))
+			}
+			return " RETURNING " + ctx.idents(sb.returnings) + " INTO " + strings.Join(ps, ","), true
+		}
+	}
+
+	ctx.addError(ErrUnsupported, "returning of "+strings.ToLower(stmt)+" not support by "+ctx.dialect.Name())
+	return "", false
+}
@@ -0,0 +1,163 @@
+// Author :		Eric<eehsiao@gmail.com>
+
+package sqlbuilder
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+)
+
+// seek is the keyset pagination condition, the rows after the last values
+// the directions are taken from the order by of builder at build time
+type seek struct {
+	sb   *SQLBuilder
+	cols []string
+	vals []Expr
+}
+
+// SeekAfter add a keyset pagination condition, the rows after the last row of previous page
+// the cols must be in the OrderByAsc() / OrderByDesc() of builder, it follow their directions
+// it is rendered as row values `(a,b) > (?,?)` when all the directions are same and the dialect support,
+// else the expanded `(a > ? OR (a = ? AND b > ?))`
+// it is `AND` with all the other where conditions, ex : `(x = ? OR y = ?) AND a > ?`
+// ex :
+// ```
+// OrderByDesc("created_at", "id").SeekAfter([]string{"created_at", "id"}, []interface{}{t, 100})
+// ```
+func (sb *SQLBuilder) SeekAfter(cols []string, vals []interface{}) *SQLBuilder {
+	if len(cols) == 0 {
+		sb.addError(ErrMissingFields, "must be support seek fields")
+		return sb
+	}
+	if len(cols) != len(vals) {
+		sb.addError(ErrFieldValueCount, "seek fields and values")
+		return sb
+	}
+
+	e := seek{sb: sb, cols: cols, vals: make([]Expr, 0, len(vals))}
+	for _, v := range vals {
+		e.vals = append(e.vals, valueExpr(v))
+	}
+	sb.wheres = append(sb.wheres, SubCond{c: true, e: e})
+
+	return sb
+}
+
+// splitSeeks split the seek conditions from the others
+// the seek must filter all the others, so they are grouped when there is `OR`
+func splitSeeks(cons []SubCond) (others []SubCond, seeks []SubCond) {
+	for _, con := range cons {
+		if _, ok := con.e.(seek); ok && con.op == "" {
+			seeks = append(seeks, con)
+		} else {
+			others = append(others, con)
+		}
+	}
+
+	return others, seeks
+}
+
+// hasOr return true if any of the conditions is joined with `OR`
+func hasOr(cons []SubCond) bool {
+	for i, con := range cons {
+		if i > 0 && !con.c {
+			return true
+		}
+	}
+
+	return false
+}
+
+// direction return the order direction of column, false if it is not in order by
+func (e seek) direction(col string) (desc bool, ok bool) {
+	for _, o := range e.sb.orders {
+		if c, isCol := o.e.(column); isCol && c.name == col {
+			return o.desc, true
+		}
+	}
+
+	return false, false
+}
+
+func (e seek) build(ctx *buildCtx) string {
+	ops := make([]string, 0, len(e.cols))
+	same := true
+	for i, c := range e.cols {
+		desc, ok := e.direction(c)
+		if !ok {
+			ctx.addError(ErrInvalidArgument, "seek field "+c+" must be in order by")
+			return ""
+		}
+		op := ">"
+		if desc {
+			op = "<"
+		}
+		same = same && (i == 0 || op == ops[0])
+		ops = append(ops, op)
+	}
+
+	if len(e.cols) == 1 {
+		return ctx.ident(e.cols[0]) + " " + ops[0] + " " + e.vals[0].build(ctx)
+	}
+	if same && ctx.dialect.RowValues() {
+		return "(" + ctx.idents(e.cols) + ") " + ops[0] + " (" + ctx.exprs(e.vals) + ")"
+	}
+
+	// a > ? OR (a = ? AND b > ?) OR (a = ? AND b = ? AND c > ?) ...
+	ors := make([]string, 0, len(e.cols))
+	for i := range e.cols {
+		ands := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			ands = append(ands, ctx.ident(e.cols[j])+" = "+e.vals[j].build(ctx))
+		}
+		ands = append(ands, ctx.ident(e.cols[i])+" "+ops[i]+" "+e.vals[i].build(ctx))
+		if i == 0 {
+			ors = append(ors, ands[0])
+		} else {
+			ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+		}
+	}
+
+	return "(" + strings.Join(ors, " OR ") + ")"
+}
+
+// EncodeCursor encode the last values of page to a opaque cursor string
+// it is base64 of json, so the values must can be json encoded
+func EncodeCursor(vals ...interface{}) (string, error) {
+	b, err := json.Marshal(vals)
+	if err != nil {
+		return "", &BuildError{Err: ErrInvalidArgument, Msg: err.Error()}
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// DecodeCursor decode the cursor string of EncodeCursor() to the values, can use with SeekAfter()
+// the integer number is decoded as int64, other number is float64,
+// the time.Time is decoded as RFC 3339 string
+func DecodeCursor(s string) ([]interface{}, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, &BuildError{Err: ErrInvalidArgument, Msg: "invalid cursor"}
+	}
+
+	var vals []interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err = dec.Decode(&vals); err != nil {
+		return nil, &BuildError{Err: ErrInvalidArgument, Msg: "invalid cursor"}
+	}
+	for i, v := range vals {
+		if n, ok := v.(json.Number); ok {
+			if iv, err := n.Int64(); err == nil {
+				vals[i] = iv
+			} else if fv, err := n.Float64(); err == nil {
+				vals[i] = fv
+			}
+		}
+	}
+
+	return vals, nil
+}
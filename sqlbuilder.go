@@ -3,8 +3,6 @@
 package sqlbuilder
 
 import (
-	"fmt"
-	"strconv"
 	"strings"
 )
 
@@ -26,12 +24,78 @@ func On(s string, o string, v interface{}) SubCond {
 
 // OnAnd its will be `and` sub condition
 func OnAnd(s string, o string, v interface{}) SubCond {
-	return SubCond{c: true, s: s, o: o, v: v}
+	return SubCond{c: true, e: compare(s, o, v)}
 }
 
 // OnOr its will be `or` sub condition
 func OnOr(s string, o string, v interface{}) SubCond {
-	return SubCond{c: false, s: s, o: o, v: v}
+	return SubCond{c: false, e: compare(s, o, v)}
+}
+
+// OnIn return a `in` sub condition
+// v can be a slice, array or a sub query *SQLBuilder, other value is a error at build time
+func OnIn(s string, v interface{}) SubCond {
+	return SubCond{c: true, e: inExpr(s, false, v)}
+}
+
+// OnNotIn return a `not in` sub condition
+// v can be a slice, array or a sub query *SQLBuilder, other value is a error at build time
+func OnNotIn(s string, v interface{}) SubCond {
+	return SubCond{c: true, e: inExpr(s, true, v)}
+}
+
+// OnExists return a `exists` sub condition with a sub query
+func OnExists(sub *SQLBuilder) SubCond {
+	return SubCond{c: true, e: exists{sub: sub}}
+}
+
+// OnNotExists return a `not exists` sub condition with a sub query
+func OnNotExists(sub *SQLBuilder) SubCond {
+	return SubCond{c: true, e: exists{sub: sub, not: true}}
+}
+
+// OnStr return a raw string sub condition
+func OnStr(s string) SubCond {
+	return SubCond{c: true, e: Var(s)}
+}
+
+// And return a group that all the sub conditions join with `and`
+// it will be rendered with parentheses
+// ex :
+// ```
+// Or(On("a", "=", 1), And(On("b", "=", 2), On("c", "=", 3)))
+// ```
+// is `(a = 1 OR (b = 2 AND c = 3))`
+func And(c ...SubCond) SubCond {
+	return SubCond{c: true, op: "AND", subs: c}
+}
+
+// Or return a group that all the sub conditions join with `or`
+// it will be rendered with parentheses
+func Or(c ...SubCond) SubCond {
+	return SubCond{c: true, op: "OR", subs: c}
+}
+
+// Not return a group that negate the sub condition
+func Not(c SubCond) SubCond {
+	return SubCond{c: true, op: "AND", not: true, subs: []SubCond{c}}
+}
+
+// Parameterized set builder to bind values as placeholder args
+// instead of inline the escaped literals
+// the args can get via BuildedArgs() or BuildedSQLArgs()
+func (sb *SQLBuilder) Parameterized(b bool) *SQLBuilder {
+	sb.parameterized = b
+
+	return sb
+}
+
+// QuoteIdentifiers set builder to quote the table and column names by the dialect
+// ex : `order` for mysql, "order" for postgresql, [order] for mssql
+func (sb *SQLBuilder) QuoteIdentifiers(b bool) *SQLBuilder {
+	sb.quoteIdent = b
+
+	return sb
 }
 
 // Distinct set builder for `distinct`
@@ -42,35 +106,71 @@ func (sb *SQLBuilder) Distinct(b bool) *SQLBuilder {
 }
 
 // Limit set builder for `limit`
-// only for Mysql, SQLite
+// Limit(n) is the max rows, Limit(m, n) is same as mysql that skip m rows then max n rows
+// it is rendered by the PageStyle() of dialect, ex :
+// `LIMIT n OFFSET m`, `OFFSET m ROWS FETCH NEXT n ROWS ONLY`, `TOP n` or wrap with `ROWNUM`
 func (sb *SQLBuilder) Limit(i ...int) *SQLBuilder {
-	if !(sb.IsMysql() || sb.IsSQLite()) {
-		sb.PanicOrErrorLog("limit only support mysql or sqlite")
-	}
 	if len(i) == 0 {
-		sb.PanicOrErrorLog("must have value for limit")
+		sb.addError(ErrInvalidArgument, "must have value for limit")
+		return sb
 	}
 
-	if len(i) == 1 {
-		sb.limit = strconv.Itoa(i[0])
-	} else if len(i) > 1 {
-		sb.limit = strconv.Itoa(i[0]) + "," + strconv.Itoa(i[1])
+	n, m := i[0], 0
+	if len(i) > 1 {
+		n, m = i[1], i[0]
+	}
+	if n <= 0 || m < 0 {
+		sb.addError(ErrInvalidArgument, "must have >=1 value for limit and >=0 value for offset")
+		return sb
+	}
+	sb.limit, sb.offset = n, m
+
+	return sb
+}
+
+// Offset set builder for skip m rows, it must be with Limit()
+// except the dialect that PageStyle() is PageOffsetFetch
+func (sb *SQLBuilder) Offset(m int) *SQLBuilder {
+	if m < 0 {
+		sb.addError(ErrInvalidArgument, "must have >=0 value for offset")
+		return sb
+	}
+
+	sb.offset = m
+
+	return sb
+}
+
+// Page set builder for the page of rows, page is start from 1
+// same as Limit(size).Offset((page-1)*size)
+func (sb *SQLBuilder) Page(page int, size int) *SQLBuilder {
+	if page <= 0 || size <= 0 {
+		sb.addError(ErrInvalidArgument, "must have >=1 value for page and size")
+		return sb
 	}
 
+	sb.limit, sb.offset = size, (page-1)*size
+
 	return sb
 }
 
 // Top set builder for `top`
-// only for Mssql
+// only for the mssql family dialect or PageStyle() is PageTop
 func (sb *SQLBuilder) Top(i int) *SQLBuilder {
-	if !sb.IsMssql() {
-		sb.PanicOrErrorLog("top only support mssql")
-	}
 	if i <= 0 {
-		sb.PanicOrErrorLog("must have >=1 value for top")
+		sb.addError(ErrInvalidArgument, "must have >=1 value for top")
+		return sb
 	}
 
-	sb.top = strconv.Itoa(i)
+	sb.top = i
+
+	return sb
+}
+
+// ForUpdate set builder for `for update` locking
+// not support by mssql and SQLite
+func (sb *SQLBuilder) ForUpdate(b bool) *SQLBuilder {
+	sb.forUpdate = b
 
 	return sb
 }
@@ -83,16 +183,33 @@ func (sb *SQLBuilder) Top(i int) *SQLBuilder {
 // ```
 func (sb *SQLBuilder) Select(s ...string) *SQLBuilder {
 	if len(s) == 0 {
-		sb.PanicOrErrorLog("must be support fileds")
+		sb.addError(ErrMissingFields, "must be support fileds")
+		return sb
 	}
 
 	for _, v := range s {
-		sb.selects = append(sb.selects, EscapeStr(v, sb.IsMysql()))
+		sb.selects = append(sb.selects, Col(v))
 	}
 
 	return sb
 }
 
+// SelectExpr set builder for `select` with expressions
+// ex :
+// ```
+// SelectExpr(Col("fieldA"), As(Fn("COUNT", Col("fieldB")), "cnt"))
+// ```
+func (sb *SQLBuilder) SelectExpr(e ...Expr) *SQLBuilder {
+	if len(e) == 0 {
+		sb.addError(ErrMissingFields, "must be support fileds")
+		return sb
+	}
+
+	sb.selects = append(sb.selects, e...)
+
+	return sb
+}
+
 // From set builder for `from`
 // params must lest one or more
 // ex :
@@ -101,18 +218,35 @@ func (sb *SQLBuilder) Select(s ...string) *SQLBuilder {
 // ```
 func (sb *SQLBuilder) From(s ...string) *SQLBuilder {
 	if len(s) == 0 {
-		sb.PanicOrErrorLog("must be support tables")
+		sb.addError(ErrMissingTable, "must be support tables")
+		return sb
 	}
 
 	for _, v := range s {
-		sb.froms = append(sb.froms, EscapeStr(v, sb.IsMysql()))
+		sb.froms = append(sb.froms, Col(v))
+	}
+
+	return sb
+}
+
+// FromExpr set builder for `from` with expressions, ex : a sub query
+// ex :
+// ```
+// FromExpr(Sub(NewSQLBuilder().Select("id").From("tblA"), "t"))
+// ```
+func (sb *SQLBuilder) FromExpr(e ...Expr) *SQLBuilder {
+	if len(e) == 0 {
+		sb.addError(ErrMissingTable, "must be support tables")
+		return sb
 	}
 
+	sb.froms = append(sb.froms, e...)
+
 	return sb
 }
 
 func (sb *SQLBuilder) clearFrom() {
-	sb.froms = make([]string, 0)
+	sb.froms = make([]Expr, 0)
 }
 
 // FromOne set builder for `from`
@@ -123,14 +257,15 @@ func (sb *SQLBuilder) clearFrom() {
 // ```
 func (sb *SQLBuilder) FromOne(s string) *SQLBuilder {
 	if s == "" {
-		sb.PanicOrErrorLog("must be support tables")
+		sb.addError(ErrMissingTable, "must be support tables")
+		return sb
 	}
 
 	if sb.IsHasFroms() {
 		sb.clearFrom()
 	}
 
-	sb.froms = append(sb.froms, EscapeStr(s, sb.IsMysql()))
+	sb.froms = append(sb.froms, Col(s))
 
 	return sb
 }
@@ -149,14 +284,11 @@ func (sb *SQLBuilder) WhereStr(s string) *SQLBuilder {
 // WhereAndStr same as WhereStr
 func (sb *SQLBuilder) WhereAndStr(s string) *SQLBuilder {
 	if s == "" {
-		sb.PanicOrErrorLog("must be support conditions")
+		sb.addError(ErrMissingCondition, "must be support conditions")
+		return sb
 	}
 
-	if sb.IsHasWheres() {
-		sb.wheres = append(sb.wheres, fmt.Sprintf("AND %s", s))
-	} else {
-		sb.wheres = append(sb.wheres, s)
-	}
+	sb.wheres = append(sb.wheres, OnStr(s))
 
 	return sb
 }
@@ -170,14 +302,11 @@ func (sb *SQLBuilder) WhereAndStr(s string) *SQLBuilder {
 // ```
 func (sb *SQLBuilder) WhereOrStr(s string) *SQLBuilder {
 	if s == "" {
-		sb.PanicOrErrorLog("must be support conditions")
+		sb.addError(ErrMissingCondition, "must be support conditions")
+		return sb
 	}
 
-	if sb.IsHasWheres() {
-		sb.wheres = append(sb.wheres, fmt.Sprintf("OR %s", s))
-	} else {
-		sb.wheres = append(sb.wheres, s)
-	}
+	sb.wheres = append(sb.wheres, SubCond{c: false, e: Var(s)})
 
 	return sb
 }
@@ -199,25 +328,11 @@ func (sb *SQLBuilder) Where(s string, o string, v interface{}) *SQLBuilder {
 // WhereAnd same as Where
 func (sb *SQLBuilder) WhereAnd(s string, o string, v interface{}) *SQLBuilder {
 	if s == "" || o == "" {
-		sb.PanicOrErrorLog("must be support conditions")
+		sb.addError(ErrMissingCondition, "must be support conditions")
+		return sb
 	}
 
-	c := ""
-	if sb.IsHasWheres() {
-		c = "AND "
-	}
-	switch v.(type) {
-	case SQLVar:
-		sb.wheres = append(sb.wheres, fmt.Sprintf("%s%s %s %s", c, EscapeStr(s, sb.IsMysql()), EscapeStr(o, sb.IsMysql()), EscapeStr(v.(SQLVar).VarS, sb.IsMysql())))
-	case string:
-		sb.wheres = append(sb.wheres, fmt.Sprintf("%s%s %s '%s'", c, EscapeStr(s, sb.IsMysql()), EscapeStr(o, sb.IsMysql()), EscapeStr(v.(string), sb.IsMysql())))
-	default:
-		if v == nil {
-			sb.wheres = append(sb.wheres, fmt.Sprintf("%s%s %s NULL", c, EscapeStr(s, sb.IsMysql()), EscapeStr(o, sb.IsMysql())))
-		} else {
-			sb.wheres = append(sb.wheres, fmt.Sprintf("%s%s %s %v", c, EscapeStr(s, sb.IsMysql()), EscapeStr(o, sb.IsMysql()), v))
-		}
-	}
+	sb.wheres = append(sb.wheres, OnAnd(s, o, v))
 
 	return sb
 }
@@ -233,82 +348,150 @@ func (sb *SQLBuilder) WhereAnd(s string, o string, v interface{}) *SQLBuilder {
 // ```
 func (sb *SQLBuilder) WhereOr(s string, o string, v interface{}) *SQLBuilder {
 	if s == "" || o == "" {
-		sb.PanicOrErrorLog("must be support conditions")
+		sb.addError(ErrMissingCondition, "must be support conditions")
+		return sb
 	}
 
-	c := ""
-	if sb.IsHasWheres() {
-		c = "OR "
+	sb.wheres = append(sb.wheres, OnOr(s, o, v))
+
+	return sb
+}
+
+// Wheres add multi conditions, each one join with its `and` / `or`
+// the group conditions via And(), Or(), Not() will be rendered with parentheses
+// ex :
+// ```
+// Wheres(On("a", "=", 1), OnOr("b", "=", 2)).Wheres(Or(On("c", "=", 3), On("d", "=", 4)))
+// ```
+// is `a = 1 OR b = 2 AND (c = 3 OR d = 4)`
+func (sb *SQLBuilder) Wheres(w ...SubCond) *SQLBuilder {
+	if len(w) == 0 {
+		sb.addError(ErrMissingCondition, "without condition")
+		return sb
 	}
-	switch v.(type) {
-	case string:
-		sb.wheres = append(sb.wheres, fmt.Sprintf("%s%s %s '%s'", c, EscapeStr(s, sb.IsMysql()), EscapeStr(o, sb.IsMysql()), EscapeStr(v.(string), sb.IsMysql())))
-	default:
-		if v == nil {
-			sb.wheres = append(sb.wheres, fmt.Sprintf("%s%s %s NULL", c, EscapeStr(s, sb.IsMysql()), EscapeStr(o, sb.IsMysql())))
-		} else {
-			sb.wheres = append(sb.wheres, fmt.Sprintf("%s%s %s %v", c, EscapeStr(s, sb.IsMysql()), EscapeStr(o, sb.IsMysql()), v))
-		}
+
+	sb.wheres = append(sb.wheres, w...)
+
+	return sb
+}
+
+// WhereIn add a `in` condition, if this not first time use, its will be `and` condition
+// v can be a slice, array or a sub query *SQLBuilder
+// the each element will be escaped or bind as placeholder
+// the empty slice will be always false
+// ex :
+// ```
+// WhereIn('fieldA', []int{1, 2, 3})
+// ```
+func (sb *SQLBuilder) WhereIn(s string, v interface{}) *SQLBuilder {
+	return sb.whereIn(s, false, v)
+}
+
+// WhereNotIn add a `not in` condition, if this not first time use, its will be `and` condition
+// v can be a slice, array or a sub query *SQLBuilder
+// the empty slice will be always true
+func (sb *SQLBuilder) WhereNotIn(s string, v interface{}) *SQLBuilder {
+	return sb.whereIn(s, true, v)
+}
+
+func (sb *SQLBuilder) whereIn(s string, not bool, v interface{}) *SQLBuilder {
+	if s == "" {
+		sb.addError(ErrMissingCondition, "must be support conditions")
+		return sb
+	}
+	e := inExpr(s, not, v)
+	if in, ok := e.(in); ok && in.bad {
+		sb.addError(ErrInvalidArgument, "the value of in must be a slice or sub query")
+		return sb
 	}
 
+	sb.wheres = append(sb.wheres, SubCond{c: true, e: e})
+
+	return sb
+}
+
+// WhereExists add a `exists` condition, if this not first time use, its will be `and` condition
+// the sub query can reference the outer alias via Var()
+// ex :
+// ```
+// WhereExists(NewSQLBuilder().Select("1").From("perm p").Where("p.user_id", "=", Var("u.id")))
+// ```
+func (sb *SQLBuilder) WhereExists(sub *SQLBuilder) *SQLBuilder {
+	return sb.whereExists(sub, false)
+}
+
+// WhereNotExists add a `not exists` condition, if this not first time use, its will be `and` condition
+func (sb *SQLBuilder) WhereNotExists(sub *SQLBuilder) *SQLBuilder {
+	return sb.whereExists(sub, true)
+}
+
+func (sb *SQLBuilder) whereExists(sub *SQLBuilder, not bool) *SQLBuilder {
+	if sub == nil {
+		sb.addError(ErrMissingCondition, "must be support sub query")
+		return sb
+	}
+
+	sb.wheres = append(sb.wheres, SubCond{c: true, e: exists{sub: sub, not: not}})
+
 	return sb
 }
 
 func (sb *SQLBuilder) join(p string, j string) *SQLBuilder {
 	if j == "" {
-		sb.PanicOrErrorLog("must be support join table")
+		sb.addError(ErrMissingTable, "must be support join table")
+		return sb
 	}
 
-	sb.joins = append(sb.joins, p+"JOIN "+EscapeStr(j, sb.IsMysql()))
+	sb.joins = append(sb.joins, join{p: p, t: Col(j)})
 
 	return sb
 }
 
 func (sb *SQLBuilder) joinOn(p string, t string, j ...SubCond) *SQLBuilder {
-	if t == "" || len(j) == 0 {
-		sb.PanicOrErrorLog("must be support join table or without condition")
-	}
-	jStr, c := "", ""
-
-	for _, con := range j {
-		if jStr == "" {
-			switch con.v.(type) {
-			case SQLVar:
-				jStr = fmt.Sprintf("%sJOIN %s ON %s %s %s", p, t, EscapeStr(con.s, sb.IsMysql()), EscapeStr(con.o, sb.IsMysql()), EscapeStr(con.v.(SQLVar).VarS, sb.IsMysql()))
-			case string:
-				jStr = fmt.Sprintf("%sJOIN %s ON %s %s '%s'", p, t, EscapeStr(con.s, sb.IsMysql()), EscapeStr(con.o, sb.IsMysql()), EscapeStr(con.v.(string), sb.IsMysql()))
-			default:
-				jStr = fmt.Sprintf("%sJOIN %s ON %s %s %v", p, t, EscapeStr(con.s, sb.IsMysql()), EscapeStr(con.o, sb.IsMysql()), con.v)
-			}
-		} else {
-			if con.c {
-				c = " AND"
-			} else {
-				c = " OR"
-			}
-			switch con.v.(type) {
-			case SQLVar:
-				jStr += fmt.Sprintf("%s %s %s %s", c, EscapeStr(con.s, sb.IsMysql()), EscapeStr(con.o, sb.IsMysql()), EscapeStr(con.v.(SQLVar).VarS, sb.IsMysql()))
-			case string:
-				jStr += fmt.Sprintf("%s %s %s '%s'", c, EscapeStr(con.s, sb.IsMysql()), EscapeStr(con.o, sb.IsMysql()), EscapeStr(con.v.(string), sb.IsMysql()))
-			default:
-				jStr += fmt.Sprintf("%s %s %s %v", c, EscapeStr(con.s, sb.IsMysql()), EscapeStr(con.o, sb.IsMysql()), con.v)
-			}
-		}
-
+	if t == "" {
+		sb.addError(ErrMissingTable, "must be support join table")
+		return sb
+	}
+	if len(j) == 0 {
+		sb.addError(ErrMissingCondition, "without condition")
+		return sb
 	}
 
-	if jStr != "" {
-		sb.joins = append(sb.joins, jStr)
+	sb.joins = append(sb.joins, join{p: p, t: Col(t), on: j})
+
+	return sb
+}
+
+// JoinExpr the join with a expression, ex : a sub query
+// p is the join type : "" (natural), `INNER`, `LEFT`, `RIGHT` or `FULL`
+// ex :
+// ```
+// JoinExpr("LEFT", Sub(NewSQLBuilder().Select("id").From("tblB"), "b"), On("a.id", "=", Var("b.id")))
+// ```
+func (sb *SQLBuilder) JoinExpr(p string, t Expr, on ...SubCond) *SQLBuilder {
+	if t == nil {
+		sb.addError(ErrMissingTable, "must be support join table")
+		return sb
+	}
+	switch p = strings.ToUpper(p); p {
+	case "":
+	case "INNER", "LEFT", "RIGHT", "FULL":
+		p += " "
+	default:
+		sb.addError(ErrInvalidArgument, "unknown join type "+p)
+		return sb
 	}
 
+	sb.joins = append(sb.joins, join{p: p, t: t, on: on})
+
 	return sb
 }
 
 // Join is a natural join
 func (sb *SQLBuilder) Join(j string) *SQLBuilder {
 	if j == "" {
-		sb.PanicOrErrorLog("must be support join table")
+		sb.addError(ErrMissingTable, "must be support join table")
+		return sb
 	}
 
 	return sb.join("", j)
@@ -317,7 +500,8 @@ func (sb *SQLBuilder) Join(j string) *SQLBuilder {
 // JoinOn the join with one condition
 func (sb *SQLBuilder) JoinOn(j string, s string, o string, v interface{}) *SQLBuilder {
 	if j == "" {
-		sb.PanicOrErrorLog("must be support join table")
+		sb.addError(ErrMissingTable, "must be support join table")
+		return sb
 	}
 
 	return sb.joinOn("", j, On(s, o, v))
@@ -326,7 +510,8 @@ func (sb *SQLBuilder) JoinOn(j string, s string, o string, v interface{}) *SQLBu
 // JoinOns the join with multi condition
 func (sb *SQLBuilder) JoinOns(j string, on ...SubCond) *SQLBuilder {
 	if j == "" {
-		sb.PanicOrErrorLog("must be support join table")
+		sb.addError(ErrMissingTable, "must be support join table")
+		return sb
 	}
 
 	return sb.joinOn("", j, on...)
@@ -335,7 +520,8 @@ func (sb *SQLBuilder) JoinOns(j string, on ...SubCond) *SQLBuilder {
 // InnerJoin the join with natural fileds
 func (sb *SQLBuilder) InnerJoin(j string) *SQLBuilder {
 	if j == "" {
-		sb.PanicOrErrorLog("must be support join table")
+		sb.addError(ErrMissingTable, "must be support join table")
+		return sb
 	}
 
 	return sb.join("INNER ", j)
@@ -344,7 +530,8 @@ func (sb *SQLBuilder) InnerJoin(j string) *SQLBuilder {
 // InnerJoinOn the join with one condition
 func (sb *SQLBuilder) InnerJoinOn(j string, s string, o string, v interface{}) *SQLBuilder {
 	if j == "" {
-		sb.PanicOrErrorLog("must be support join table")
+		sb.addError(ErrMissingTable, "must be support join table")
+		return sb
 	}
 
 	return sb.joinOn("INNER ", j, On(s, o, v))
@@ -353,7 +540,8 @@ func (sb *SQLBuilder) InnerJoinOn(j string, s string, o string, v interface{}) *
 // InnerJoinOns the join with multi condition
 func (sb *SQLBuilder) InnerJoinOns(j string, on ...SubCond) *SQLBuilder {
 	if j == "" {
-		sb.PanicOrErrorLog("must be support join table")
+		sb.addError(ErrMissingTable, "must be support join table")
+		return sb
 	}
 
 	return sb.joinOn("INNER ", j, on...)
@@ -362,7 +550,8 @@ func (sb *SQLBuilder) InnerJoinOns(j string, on ...SubCond) *SQLBuilder {
 // LeftJoin the join with natural fileds
 func (sb *SQLBuilder) LeftJoin(j string) *SQLBuilder {
 	if j == "" {
-		sb.PanicOrErrorLog("must be support join table")
+		sb.addError(ErrMissingTable, "must be support join table")
+		return sb
 	}
 
 	return sb.join("LEFT ", j)
@@ -371,7 +560,8 @@ func (sb *SQLBuilder) LeftJoin(j string) *SQLBuilder {
 // LeftJoinOn the join with one condition
 func (sb *SQLBuilder) LeftJoinOn(j string, s string, o string, v interface{}) *SQLBuilder {
 	if j == "" {
-		sb.PanicOrErrorLog("must be support join table")
+		sb.addError(ErrMissingTable, "must be support join table")
+		return sb
 	}
 
 	return sb.joinOn("LEFT ", j, On(s, o, v))
@@ -380,7 +570,8 @@ func (sb *SQLBuilder) LeftJoinOn(j string, s string, o string, v interface{}) *S
 // LeftJoinOns the join with multi condition
 func (sb *SQLBuilder) LeftJoinOns(j string, on ...SubCond) *SQLBuilder {
 	if j == "" {
-		sb.PanicOrErrorLog("must be support join table")
+		sb.addError(ErrMissingTable, "must be support join table")
+		return sb
 	}
 
 	return sb.joinOn("LEFT ", j, on...)
@@ -389,7 +580,8 @@ func (sb *SQLBuilder) LeftJoinOns(j string, on ...SubCond) *SQLBuilder {
 // RightJoin the join with natural fileds
 func (sb *SQLBuilder) RightJoin(j string) *SQLBuilder {
 	if j == "" {
-		sb.PanicOrErrorLog("must be support join table")
+		sb.addError(ErrMissingTable, "must be support join table")
+		return sb
 	}
 
 	return sb.join("RIGHT ", j)
@@ -398,7 +590,8 @@ func (sb *SQLBuilder) RightJoin(j string) *SQLBuilder {
 // RightJoinOn the join with one condition
 func (sb *SQLBuilder) RightJoinOn(j string, s string, o string, v interface{}) *SQLBuilder {
 	if j == "" {
-		sb.PanicOrErrorLog("must be support join table")
+		sb.addError(ErrMissingTable, "must be support join table")
+		return sb
 	}
 
 	return sb.joinOn("RIGHT ", j, On(s, o, v))
@@ -407,7 +600,8 @@ func (sb *SQLBuilder) RightJoinOn(j string, s string, o string, v interface{}) *
 // RightJoinOns the join with multi condition
 func (sb *SQLBuilder) RightJoinOns(j string, on ...SubCond) *SQLBuilder {
 	if j == "" {
-		sb.PanicOrErrorLog("must be support join table")
+		sb.addError(ErrMissingTable, "must be support join table")
+		return sb
 	}
 
 	return sb.joinOn("RIGHT ", j, on...)
@@ -416,7 +610,8 @@ func (sb *SQLBuilder) RightJoinOns(j string, on ...SubCond) *SQLBuilder {
 // FullJoin the join with natural fileds
 func (sb *SQLBuilder) FullJoin(j string) *SQLBuilder {
 	if j == "" {
-		sb.PanicOrErrorLog("must be support join table")
+		sb.addError(ErrMissingTable, "must be support join table")
+		return sb
 	}
 
 	return sb.join("FULL ", j)
@@ -425,7 +620,8 @@ func (sb *SQLBuilder) FullJoin(j string) *SQLBuilder {
 // FullJoinOn the join with one condition
 func (sb *SQLBuilder) FullJoinOn(j string, s string, o string, v interface{}) *SQLBuilder {
 	if j == "" {
-		sb.PanicOrErrorLog("must be support join table")
+		sb.addError(ErrMissingTable, "must be support join table")
+		return sb
 	}
 
 	return sb.joinOn("FULL ", j, On(s, o, v))
@@ -434,7 +630,8 @@ func (sb *SQLBuilder) FullJoinOn(j string, s string, o string, v interface{}) *S
 // FullJoinOns the join with multi condition
 func (sb *SQLBuilder) FullJoinOns(j string, on ...SubCond) *SQLBuilder {
 	if j == "" {
-		sb.PanicOrErrorLog("must be support join table")
+		sb.addError(ErrMissingTable, "must be support join table")
+		return sb
 	}
 
 	return sb.joinOn("FULL ", j, on...)
@@ -444,16 +641,29 @@ func (sb *SQLBuilder) FullJoinOns(j string, on ...SubCond) *SQLBuilder {
 // fileds must be same as select fileds
 func (sb *SQLBuilder) GroupBy(s ...string) *SQLBuilder {
 	if len(s) == 0 {
-		sb.PanicOrErrorLog("must be support group fileds")
+		sb.addError(ErrMissingFields, "must be support group fileds")
+		return sb
 	}
 
 	for _, v := range s {
-		sb.groups = append(sb.groups, EscapeStr(v, sb.IsMysql()))
+		sb.groups = append(sb.groups, Col(v))
 	}
 
 	return sb
 }
 
+// GroupByExpr with expressions, ex : Case()
+func (sb *SQLBuilder) GroupByExpr(es ...Expr) *SQLBuilder {
+	if len(es) == 0 {
+		sb.addError(ErrMissingFields, "must be support group expressions")
+		return sb
+	}
+
+	sb.groups = append(sb.groups, es...)
+
+	return sb
+}
+
 // OrderBy with fileds
 // default Asc
 func (sb *SQLBuilder) OrderBy(s ...string) *SQLBuilder {
@@ -463,10 +673,13 @@ func (sb *SQLBuilder) OrderBy(s ...string) *SQLBuilder {
 // OrderByAsc with fileds
 func (sb *SQLBuilder) OrderByAsc(s ...string) *SQLBuilder {
 	if len(s) == 0 {
-		sb.PanicOrErrorLog("must be support order fileds")
+		sb.addError(ErrMissingFields, "must be support order fileds")
+		return sb
 	}
 
-	sb.orders = append(sb.orders, EscapeStr(strings.Join(s, ","), sb.IsMysql())+" ASC")
+	for _, v := range s {
+		sb.orders = append(sb.orders, order{e: Col(v)})
+	}
 
 	return sb
 }
@@ -474,60 +687,70 @@ func (sb *SQLBuilder) OrderByAsc(s ...string) *SQLBuilder {
 // OrderByDesc with fileds
 func (sb *SQLBuilder) OrderByDesc(s ...string) *SQLBuilder {
 	if len(s) == 0 {
-		sb.PanicOrErrorLog("must be support order fileds")
+		sb.addError(ErrMissingFields, "must be support order fileds")
+		return sb
 	}
 
-	sb.orders = append(sb.orders, EscapeStr(strings.Join(s, ","), sb.IsMysql())+" DESC")
+	for _, v := range s {
+		sb.orders = append(sb.orders, order{e: Col(v), desc: true})
+	}
+
+	return sb
+}
+
+// OrderByExpr with expressions, ex : a window function of Over()
+func (sb *SQLBuilder) OrderByExpr(es ...Expr) *SQLBuilder {
+	if len(es) == 0 {
+		sb.addError(ErrMissingFields, "must be support order expressions")
+		return sb
+	}
+
+	for _, e := range es {
+		sb.orders = append(sb.orders, order{e: e})
+	}
+
+	return sb
+}
+
+// OrderByDescExpr with expressions that order by `DESC`
+func (sb *SQLBuilder) OrderByDescExpr(es ...Expr) *SQLBuilder {
+	if len(es) == 0 {
+		sb.addError(ErrMissingFields, "must be support order expressions")
+		return sb
+	}
+
+	for _, e := range es {
+		sb.orders = append(sb.orders, order{e: e, desc: true})
+	}
 
 	return sb
 }
 
 func (sb *SQLBuilder) having(h ...SubCond) *SQLBuilder {
 	if len(h) == 0 {
-		sb.PanicOrErrorLog("without condition")
+		sb.addError(ErrMissingCondition, "without condition")
+		return sb
 	}
 	if !sb.IsHasGroups() {
-		sb.PanicOrErrorLog("must be set group by first")
-	}
-
-	c := ""
-
-	for _, con := range h {
-		if sb.havings == "" {
-			switch con.v.(type) {
-			case SQLVar:
-				sb.havings = fmt.Sprintf("%s %s %s", EscapeStr(con.s, sb.IsMysql()), EscapeStr(con.o, sb.IsMysql()), EscapeStr(con.v.(SQLVar).VarS, sb.IsMysql()))
-			case string:
-				sb.havings = fmt.Sprintf("%s %s '%s'", EscapeStr(con.s, sb.IsMysql()), EscapeStr(con.o, sb.IsMysql()), EscapeStr(con.v.(string), sb.IsMysql()))
-			default:
-				sb.havings = fmt.Sprintf("%s %s %v", EscapeStr(con.s, sb.IsMysql()), EscapeStr(con.o, sb.IsMysql()), con.v)
-			}
-		} else {
-			if con.c {
-				c = " AND"
-			} else {
-				c = " OR"
-			}
-			switch con.v.(type) {
-			case SQLVar:
-				sb.havings += fmt.Sprintf("%s %s %s %s", c, EscapeStr(con.s, sb.IsMysql()), EscapeStr(con.o, sb.IsMysql()), EscapeStr(con.v.(SQLVar).VarS, sb.IsMysql()))
-			case string:
-				sb.havings += fmt.Sprintf("%s %s %s '%s'", c, EscapeStr(con.s, sb.IsMysql()), EscapeStr(con.o, sb.IsMysql()), EscapeStr(con.v.(string), sb.IsMysql()))
-			default:
-				sb.havings += fmt.Sprintf("%s %s %s %v", c, EscapeStr(con.s, sb.IsMysql()), EscapeStr(con.o, sb.IsMysql()), con.v)
-			}
-		}
-
+		sb.addError(ErrMissingGroupBy, "must be set group by first")
+		return sb
 	}
 
+	sb.havings = append(sb.havings, h...)
+
 	return sb
 }
 
 // Having with one condition
 // its will overwrite having section
 func (sb *SQLBuilder) Having(s string, o string, v interface{}) *SQLBuilder {
-	if s == "" || !sb.IsHasGroups() {
-		sb.PanicOrErrorLog("must be support having condition or set group by first")
+	if s == "" {
+		sb.addError(ErrMissingCondition, "must be support having condition")
+		return sb
+	}
+	if !sb.IsHasGroups() {
+		sb.addError(ErrMissingGroupBy, "must be set group by first")
+		return sb
 	}
 
 	return sb.having(On(s, o, v))
@@ -536,10 +759,12 @@ func (sb *SQLBuilder) Having(s string, o string, v interface{}) *SQLBuilder {
 // Havings with multi conditions
 func (sb *SQLBuilder) Havings(h ...SubCond) *SQLBuilder {
 	if len(h) == 0 {
-		sb.PanicOrErrorLog("without condition")
+		sb.addError(ErrMissingCondition, "without condition")
+		return sb
 	}
 	if !sb.IsHasGroups() {
-		sb.PanicOrErrorLog("must be set group by first")
+		sb.addError(ErrMissingGroupBy, "must be set group by first")
+		return sb
 	}
 
 	return sb.having(h...)
@@ -548,7 +773,8 @@ func (sb *SQLBuilder) Havings(h ...SubCond) *SQLBuilder {
 // Set with Set{K string, V interface{}} structs
 func (sb *SQLBuilder) Set(s []Set) *SQLBuilder {
 	if len(s) == 0 {
-		sb.PanicOrErrorLog("must be support set fileds : values")
+		sb.addError(ErrMissingValues, "must be support set fileds : values")
+		return sb
 	}
 
 	for _, set := range s {
@@ -561,10 +787,11 @@ func (sb *SQLBuilder) Set(s []Set) *SQLBuilder {
 // Into for set insert table
 func (sb *SQLBuilder) Into(s string) *SQLBuilder {
 	if s == "" {
-		sb.PanicOrErrorLog("must be support table")
+		sb.addError(ErrMissingTable, "must be support table")
+		return sb
 	}
 
-	sb.into = EscapeStr(s, sb.IsMysql())
+	sb.into = s
 
 	return sb
 }
@@ -572,14 +799,16 @@ func (sb *SQLBuilder) Into(s string) *SQLBuilder {
 // Fields for set update fields
 func (sb *SQLBuilder) Fields(s ...string) *SQLBuilder {
 	if len(s) == 0 {
-		sb.PanicOrErrorLog("must be support fileds")
+		sb.addError(ErrMissingFields, "must be support fileds")
+		return sb
 	}
 	if sb.IsHasValues() {
-		sb.PanicOrErrorLog("cannot add fileds after Values()")
+		sb.addError(ErrFieldValueCount, "cannot add fileds after Values()")
+		return sb
 	}
 
 	for _, v := range s {
-		sb.fields = append(sb.fields, EscapeStr(v, sb.IsMysql()))
+		sb.fields = append(sb.fields, v)
 	}
 
 	return sb
@@ -588,7 +817,8 @@ func (sb *SQLBuilder) Fields(s ...string) *SQLBuilder {
 // Values for set update values
 func (sb *SQLBuilder) Values(s ...interface{}) *SQLBuilder {
 	if len(s) == 0 {
-		sb.PanicOrErrorLog("must be support fileds")
+		sb.addError(ErrMissingValues, "must be support values")
+		return sb
 	}
 
 	fieldCnt := sb.GetFieldsCount()
@@ -599,7 +829,7 @@ func (sb *SQLBuilder) Values(s ...interface{}) *SQLBuilder {
 		}
 		sb.values = append(sb.values, vs)
 	} else {
-		sb.PanicOrErrorLog("values count not equal fileds count")
+		sb.addError(ErrFieldValueCount, "values count not equal fileds count")
 	}
 
 	return sb
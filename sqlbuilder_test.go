@@ -3,6 +3,12 @@
 package sqlbuilder
 
 import (
+	"bytes"
+	"errors"
+	"log"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -77,7 +83,7 @@ func TestSQLBuilder_BuildedSQL(t *testing.T) {
 					Havings(On("count(User)", ">", 2)).
 					BuildSelectSQL()
 			},
-			wantSQL: `SELECT Host,User,Select_priv FROM user ORDER BY Host ASC,User ASC,Select_priv DESC GROUP BY Host,User,Select_priv HAVING count(Host) > 1 AND count(User) > 2`,
+			wantSQL: `SELECT Host,User,Select_priv FROM user GROUP BY Host,User,Select_priv HAVING count(Host) > 1 AND count(User) > 2 ORDER BY Host ASC,User ASC,Select_priv DESC`,
 		},
 		{
 			name: "case 5 : DELETE",
@@ -149,3 +155,2206 @@ func TestSQLBuilder_BuildedSQL(t *testing.T) {
 		})
 	}
 }
+
+func TestSQLBuilder_BuildedSQLArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		fn       func(sb *SQLBuilder)
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name: "case 1 : SELECT",
+			fn: func(sb *SQLBuilder) {
+				sb.Select("Host", "User").
+					From("user a").
+					JoinOns("priv c",
+						On("c.abc", "=", 1),
+						OnAnd("c.def", "=", Var("a.def")),
+					).
+					Where("Host", "=", "localhost").
+					WhereOr("User", "=", "root").
+					Where("Select_priv", "is", nil).
+					GroupBy("Host", "User").
+					Having("count(Host)", ">", 2).
+					BuildSelectSQL()
+			},
+			wantSQL:  `SELECT Host,User FROM user a JOIN priv c ON c.abc = ? AND c.def = a.def WHERE Host = ? OR User = ? AND Select_priv is NULL GROUP BY Host,User HAVING count(Host) > ?`,
+			wantArgs: []interface{}{1, "localhost", "root", 2},
+		},
+		{
+			name: "case 2 : UPDATE",
+			fn: func(sb *SQLBuilder) {
+				sb.Set([]Set{{"foo", 1}, {"bar", "'2'"}, {"dt", Var("current_timestamp")}, {"testNil", nil}}).
+					From("user").
+					Where("abc", "=", true).
+					BuildUpdateSQL()
+			},
+			wantSQL:  `UPDATE user SET foo=?,bar=?,dt=current_timestamp,testNil=NULL WHERE abc = ?`,
+			wantArgs: []interface{}{1, "'2'", true},
+		},
+		{
+			name: "case 3 : Bulk INSERT",
+			fn: func(sb *SQLBuilder) {
+				sb.Fields("testDt", "Host", "User").
+					Values(Var("current_timestamp"), 1, "a").
+					Values(nil, 2, "b").
+					Into("user").
+					BuildBulkInsertSQL()
+			},
+			wantSQL:  `INSERT INTO user (testDt,Host,User) VALUES (current_timestamp,?,?),(NULL,?,?)`,
+			wantArgs: []interface{}{1, "a", 2, "b"},
+		},
+		{
+			name: "case 4 : DELETE",
+			fn: func(sb *SQLBuilder) {
+				sb.From("user").
+					Where("Host", "=", "x").
+					BuildDeleteSQL()
+			},
+			wantSQL:  `DELETE FROM user WHERE Host = ?`,
+			wantArgs: []interface{}{"x"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := NewSQLBuilder("mysql").Parameterized(true)
+			tt.fn(sb)
+			gotSQL, gotArgs := sb.BuildedSQLArgs()
+			if gotSQL != tt.wantSQL {
+				t.Errorf("SQLBuilder.BuildedSQLArgs() sql = %v, want %v", gotSQL, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("SQLBuilder.BuildedSQLArgs() args = %v, want %v", gotArgs, tt.wantArgs)
+			}
+			sb.Release()
+		})
+	}
+}
+
+func TestSQLBuilder_Placeholder(t *testing.T) {
+	fn := func(sb *SQLBuilder) {
+		sb.Select("a", "b").
+			From("tblA a").
+			JoinOn("tblB b", "b.id", "=", 1).
+			Where("a", "=", "x").
+			Where("b", "in", Var("(1,2)")).
+			GroupBy("a", "b").
+			Having("count(a)", ">", 2).
+			BuildSelectSQL()
+	}
+	tests := []struct {
+		name    string
+		driver  string
+		wantSQL string
+	}{
+		{
+			name:    "mysql",
+			driver:  "mysql",
+			wantSQL: `SELECT a,b FROM tblA a JOIN tblB b ON b.id = ? WHERE a = ? AND b in (1,2) GROUP BY a,b HAVING count(a) > ?`,
+		},
+		{
+			name:    "SQLite",
+			driver:  "SQLite",
+			wantSQL: `SELECT a,b FROM tblA a JOIN tblB b ON b.id = ? WHERE a = ? AND b in (1,2) GROUP BY a,b HAVING count(a) > ?`,
+		},
+		{
+			name:    "postgresql",
+			driver:  "postgresql",
+			wantSQL: `SELECT a,b FROM tblA a JOIN tblB b ON b.id = $1 WHERE a = $2 AND b in (1,2) GROUP BY a,b HAVING count(a) > $3`,
+		},
+		{
+			name:    "mssql",
+			driver:  "mssql",
+			wantSQL: `SELECT a,b FROM tblA a JOIN tblB b ON b.id = @p1 WHERE a = @p2 AND b in (1,2) GROUP BY a,b HAVING count(a) > @p3`,
+		},
+		{
+			name:    "oracle",
+			driver:  "oracle",
+			wantSQL: `SELECT a,b FROM tblA a JOIN tblB b ON b.id = :1 WHERE a = :2 AND b in (1,2) GROUP BY a,b HAVING count(a) > :3`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := NewSQLBuilder(tt.driver).Parameterized(true)
+			fn(sb)
+			if gotSQL := sb.BuildedSQL(); gotSQL != tt.wantSQL {
+				t.Errorf("SQLBuilder.BuildedSQL() = %v, want %v", gotSQL, tt.wantSQL)
+			}
+			if gotArgs := sb.BuildedArgs(); !reflect.DeepEqual(gotArgs, []interface{}{1, "x", 2}) {
+				t.Errorf("SQLBuilder.BuildedArgs() = %v, want %v", gotArgs, []interface{}{1, "x", 2})
+			}
+			sb.Release()
+		})
+	}
+
+	t.Run("postgresql UPDATE", func(t *testing.T) {
+		sb := NewSQLBuilder("postgresql").Parameterized(true)
+		sb.Set([]Set{{"foo", 1}, {"bar", "b"}}).
+			From("user").
+			Where("abc", "=", 3).
+			BuildUpdateSQL()
+		if want := `UPDATE user SET foo=$1,bar=$2 WHERE abc = $3`; sb.BuildedSQL() != want {
+			t.Errorf("SQLBuilder.BuildedSQL() = %v, want %v", sb.BuildedSQL(), want)
+		}
+	})
+
+	t.Run("postgresql Bulk INSERT", func(t *testing.T) {
+		sb := NewSQLBuilder("postgresql").Parameterized(true)
+		sb.Fields("a", "b").
+			Values(1, "x").
+			Values(2, "y").
+			Into("tblA").
+			BuildBulkInsertSQL()
+		if want := `INSERT INTO tblA (a,b) VALUES ($1,$2),($3,$4)`; sb.BuildedSQL() != want {
+			t.Errorf("SQLBuilder.BuildedSQL() = %v, want %v", sb.BuildedSQL(), want)
+		}
+	})
+}
+
+func TestSQLBuilder_EscapeStr(t *testing.T) {
+	tests := []struct {
+		name    string
+		driver  string
+		fn      func(sb *SQLBuilder) *SQLBuilder
+		wantSQL string
+	}{
+		{
+			name:    "mysql backslash",
+			driver:  "mysql",
+			fn:      func(sb *SQLBuilder) *SQLBuilder { return sb.Where("name", "=", `x\' OR 1=1 -- `) },
+			wantSQL: `SELECT a FROM tblA WHERE name = 'x\\\' OR 1=1 -- '`,
+		},
+		{
+			name:    "postgresql quotes",
+			driver:  "postgresql",
+			fn:      func(sb *SQLBuilder) *SQLBuilder { return sb.Where("name", "=", `say "hi" it's \`) },
+			wantSQL: `SELECT a FROM tblA WHERE name = 'say "hi" it''s \'`,
+		},
+		{
+			name:    "mssql quotes",
+			driver:  "mssql",
+			fn:      func(sb *SQLBuilder) *SQLBuilder { return sb.Where("name", "=", `say "hi" it's`) },
+			wantSQL: `SELECT a FROM tblA WHERE name = 'say "hi" it''s'`,
+		},
+		{
+			name:    "mysql like",
+			driver:  "mysql",
+			fn:      func(sb *SQLBuilder) *SQLBuilder { return sb.WhereContains("name", `a\_`) },
+			wantSQL: `SELECT a FROM tblA WHERE name LIKE '%a\\!_%' ESCAPE '!'`,
+		},
+		{
+			name:    "SQLite like",
+			driver:  "SQLite",
+			fn:      func(sb *SQLBuilder) *SQLBuilder { return sb.WhereContains("name", `a\_`) },
+			wantSQL: `SELECT a FROM tblA WHERE name LIKE '%a\!_%' ESCAPE '!'`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := NewSQLBuilder(tt.driver)
+			sql, _, err := tt.fn(sb.Select("a").From("tblA")).BuildSelect()
+			if err != nil || sql != tt.wantSQL {
+				t.Errorf("SQLBuilder.BuildSelect() = %v, %v, want %v", sql, err, tt.wantSQL)
+			}
+			sb.Release()
+		})
+	}
+}
+
+type testCockroachDialect struct {
+	PostgresqlDialect
+}
+
+func (testCockroachDialect) Name() string { return "cockroachdb" }
+
+type testLibsqlDialect struct {
+	SQLiteDialect
+}
+
+func (testLibsqlDialect) Name() string { return "libsql" }
+
+func TestRegisterDialect(t *testing.T) {
+	RegisterDialect(testCockroachDialect{})
+
+	sb := NewSQLBuilder("cockroachdb").Parameterized(true)
+	if got := sb.GetDriverType(); got != "cockroachdb" {
+		t.Errorf("SQLBuilder.GetDriverType() = %v, want %v", got, "cockroachdb")
+	}
+	sb.Select("a").From("tblA").Where("a", "=", 1).Limit(10).BuildSelectSQL()
+	if want := `SELECT a FROM tblA WHERE a = $1 LIMIT 10`; sb.BuildedSQL() != want {
+		t.Errorf("SQLBuilder.BuildedSQL() = %v, want %v", sb.BuildedSQL(), want)
+	}
+
+	sb = NewSQLBuilder("mssql")
+	sb.Select("a").From("tblA").Where("a", "=", true).Top(1).BuildSelectSQL()
+	if want := `SELECT TOP 1 a FROM tblA WHERE a = 1`; sb.BuildedSQL() != want {
+		t.Errorf("SQLBuilder.BuildedSQL() = %v, want %v", sb.BuildedSQL(), want)
+	}
+
+	sb.SetDriverType("unknown")
+	if got := sb.GetDriverType(); got != "mssql" {
+		t.Errorf("SQLBuilder.GetDriverType() = %v, want %v", got, "mssql")
+	}
+
+	RegisterDialect(testLibsqlDialect{})
+	sql, _, err := NewSQLBuilder("libsql").Into("tblA").Fields("a").Values(1).BuildInsertOrReplace()
+	if want := `INSERT OR REPLACE INTO tblA (a) VALUES (1)`; err != nil || sql != want {
+		t.Errorf("SQLBuilder.BuildInsertOrReplace() = %v, %v, want %v", sql, err, want)
+	}
+	_, _, err = NewSQLBuilder("cockroachdb").Into("tblA").Fields("a").Values(1).BuildInsertOrReplace()
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("SQLBuilder.BuildInsertOrReplace() error = %v, want %v", err, ErrUnsupported)
+	}
+}
+
+func TestSQLBuilder_QuoteIdentifiers(t *testing.T) {
+	tests := []struct {
+		name    string
+		driver  string
+		fn      func(sb *SQLBuilder)
+		wantSQL string
+	}{
+		{
+			name:   "mysql SELECT",
+			driver: "mysql",
+			fn: func(sb *SQLBuilder) {
+				sb.Select("u.order", "u.*", "count(*)", "1", "name AS n").
+					From("shop.user u").
+					LeftJoinOn("`group` g", "g.id", "=", Var("u.group_id")).
+					Where("u.desc", "=", "x").
+					OrderByDesc("u.order").
+					BuildSelectSQL()
+			},
+			wantSQL: "SELECT `u`.`order`,`u`.*,count(*),1,`name` AS `n` FROM `shop`.`user` `u` LEFT JOIN `group` `g` ON `g`.`id` = u.group_id WHERE `u`.`desc` = 'x' ORDER BY `u`.`order` DESC",
+		},
+		{
+			name:   "postgresql UPDATE",
+			driver: "postgresql",
+			fn: func(sb *SQLBuilder) {
+				sb.Set([]Set{{"order", 1}, {`"my col"`, "a"}}).
+					From("public.user").
+					Where("user", "=", 2).
+					BuildUpdateSQL()
+			},
+			wantSQL: `UPDATE "public"."user" SET "order"=1,"my col"='a' WHERE "user" = 2`,
+		},
+		{
+			name:   "mssql INSERT",
+			driver: "mssql",
+			fn: func(sb *SQLBuilder) {
+				sb.Fields("order", "[first name]").
+					Values(1, "a").
+					Into("dbo.user").
+					BuildInsertSQL()
+			},
+			wantSQL: `INSERT INTO [dbo].[user] ([order],[first name]) VALUES (1,'a')`,
+		},
+		{
+			name:   "oracle DELETE",
+			driver: "oracle",
+			fn: func(sb *SQLBuilder) {
+				sb.From("user").
+					Where("order", "=", 1).
+					BuildDeleteSQL()
+			},
+			wantSQL: `DELETE FROM "user" WHERE "order" = 1`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := NewSQLBuilder(tt.driver).QuoteIdentifiers(true)
+			tt.fn(sb)
+			if gotSQL := sb.BuildedSQL(); gotSQL != tt.wantSQL {
+				t.Errorf("SQLBuilder.BuildedSQL() = %v, want %v", gotSQL, tt.wantSQL)
+			}
+			sb.Release()
+		})
+	}
+}
+
+func TestSQLBuilder_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		driver  string
+		fn      func(sb *SQLBuilder) (string, []interface{}, error)
+		wantErr error
+	}{
+		{
+			name:   "missing table",
+			driver: "mysql",
+			fn: func(sb *SQLBuilder) (string, []interface{}, error) {
+				return sb.Select("a").BuildSelect()
+			},
+			wantErr: ErrMissingTable,
+		},
+		{
+			name:   "field value count",
+			driver: "mysql",
+			fn: func(sb *SQLBuilder) (string, []interface{}, error) {
+				return sb.Into("tblA").Fields("a", "b").Values(1).BuildInsert()
+			},
+			wantErr: ErrFieldValueCount,
+		},
+		{
+			name:   "unsupported clause",
+			driver: "postgresql",
+			fn: func(sb *SQLBuilder) (string, []interface{}, error) {
+				return sb.Select("a").From("tblA").Top(1).BuildSelect()
+			},
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:   "having without group by",
+			driver: "mysql",
+			fn: func(sb *SQLBuilder) (string, []interface{}, error) {
+				return sb.Select("a").From("tblA").Having("count(a)", ">", 1).BuildSelect()
+			},
+			wantErr: ErrMissingGroupBy,
+		},
+		{
+			name:   "update without sets",
+			driver: "mysql",
+			fn: func(sb *SQLBuilder) (string, []interface{}, error) {
+				return sb.From("tblA").Where("a", "=", 1).BuildUpdate()
+			},
+			wantErr: ErrMissingValues,
+		},
+		{
+			name:   "empty condition",
+			driver: "mysql",
+			fn: func(sb *SQLBuilder) (string, []interface{}, error) {
+				return sb.SetErrorPolicy(ErrorCollect).Select("a").From("tblA").Wheres(SubCond{}).BuildSelect()
+			},
+			wantErr: ErrMissingCondition,
+		},
+		{
+			name:   "nil sub query",
+			driver: "mysql",
+			fn: func(sb *SQLBuilder) (string, []interface{}, error) {
+				return sb.Select("a").FromExpr(Sub(nil, "t")).BuildSelect()
+			},
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:   "nil sub query value",
+			driver: "mysql",
+			fn: func(sb *SQLBuilder) (string, []interface{}, error) {
+				return sb.Select("a").From("tblA").Where("x", "=", (*SQLBuilder)(nil)).BuildSelect()
+			},
+			wantErr: ErrInvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := NewSQLBuilder(tt.driver)
+			sql, args, err := tt.fn(sb)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("SQLBuilder.Build() error = %v, want %v", err, tt.wantErr)
+			}
+			if sql != "" || args != nil || sb.BuildedSQL() != "" {
+				t.Errorf("SQLBuilder.Build() = %v, %v, want empty", sql, args)
+			}
+			sb.Release()
+		})
+	}
+
+	t.Run("BuildSelectSQL panic", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("SQLBuilder.BuildSelectSQL() without panic")
+			}
+		}()
+		NewSQLBuilder().Select("a").BuildSelectSQL()
+	})
+
+	t.Run("ClearBuilder", func(t *testing.T) {
+		sb := NewSQLBuilder().Select()
+		if !errors.Is(sb.Err(), ErrMissingFields) {
+			t.Errorf("SQLBuilder.Err() = %v, want %v", sb.Err(), ErrMissingFields)
+		}
+		sb.ClearBuilder()
+		sql, _, err := sb.Select("a").From("tblA").BuildSelect()
+		if err != nil || sql != "SELECT a FROM tblA" {
+			t.Errorf("SQLBuilder.BuildSelect() = %v, %v", sql, err)
+		}
+	})
+}
+
+func TestSQLBuilder_ErrorPolicy(t *testing.T) {
+	t.Run("log", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		sb := NewSQLBuilder().SetLogger(log.New(buf, "", 0))
+		sb.SwitchPanicToErrorLog(true)
+		sb.Select("a").BuildSelectSQL()
+		if !strings.Contains(buf.String(), ErrMissingTable.Error()) {
+			t.Errorf("logger got %v, want %v", buf.String(), ErrMissingTable)
+		}
+
+		// another builder still panic
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("SQLBuilder.BuildSelectSQL() without panic")
+			}
+		}()
+		NewSQLBuilder().Select("a").BuildSelectSQL()
+	})
+
+	t.Run("log func", func(t *testing.T) {
+		var got error
+		sb := NewSQLBuilder().
+			SetErrorPolicy(ErrorLog).
+			SetErrorLogFunc(func(err error) { got = err })
+		sb.Into("tblA").Fields("a").Values(1, 2).BuildInsertSQL()
+		if !errors.Is(got, ErrFieldValueCount) {
+			t.Errorf("log func got %v, want %v", got, ErrFieldValueCount)
+		}
+	})
+
+	t.Run("collect", func(t *testing.T) {
+		sb := NewSQLBuilder().SetErrorPolicy(ErrorCollect)
+		sb.From("tblA").BuildSelectSQL()
+		if !errors.Is(sb.Err(), ErrMissingFields) || sb.BuildedSQL() != "" {
+			t.Errorf("SQLBuilder.Err() = %v, want %v", sb.Err(), ErrMissingFields)
+		}
+	})
+
+	t.Run("goroutines", func(t *testing.T) {
+		wg := sync.WaitGroup{}
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				sb := NewSQLBuilder().SetErrorPolicy(ErrorPolicy(i % 3))
+				sb.SwitchPanicToErrorLog(i%2 == 0)
+				sb.SetLogger(log.New(&bytes.Buffer{}, "", 0))
+				sb.Select("a").From("tblA").BuildSelectSQL()
+			}(i)
+		}
+		wg.Wait()
+	})
+}
+
+func TestSQLBuilder_CondGroup(t *testing.T) {
+	tests := []struct {
+		name     string
+		fn       func(sb *SQLBuilder)
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name: "case 1 : WHERE",
+			fn: func(sb *SQLBuilder) {
+				sb.Select("a").
+					From("tblA").
+					Where("a", "=", 1).
+					Wheres(OnOr("b", "=", 2)).
+					Wheres(Or(On("c", "=", 3), And(On("d", "=", 4), OnStr("e IS NULL")))).
+					Wheres(Not(Or(On("f", "=", 5), On("g", "=", 6)))).
+					Wheres(Not(On("h", "=", 7))).
+					BuildSelectSQL()
+			},
+			wantSQL:  `SELECT a FROM tblA WHERE a = $1 OR b = $2 AND (c = $3 OR (d = $4 AND e IS NULL)) AND NOT (f = $5 OR g = $6) AND NOT (h = $7)`,
+			wantArgs: []interface{}{1, 2, 3, 4, 5, 6, 7},
+		},
+		{
+			name: "case 2 : JOIN ON, HAVING",
+			fn: func(sb *SQLBuilder) {
+				sb.Select("a.id", "count(*)").
+					From("tblA a").
+					LeftJoinOns("tblB b",
+						On("a.id", "=", Var("b.a_id")),
+						Or(On("b.type", "=", "x"), On("b.type", "is", nil)),
+					).
+					GroupBy("a.id").
+					Havings(Or(On("count(*)", ">", 1), On("max(b.v)", "<", 9))).
+					BuildSelectSQL()
+			},
+			wantSQL:  `SELECT a.id,count(*) FROM tblA a LEFT JOIN tblB b ON a.id = b.a_id AND (b.type = $1 OR b.type is NULL) GROUP BY a.id HAVING (count(*) > $2 OR max(b.v) < $3)`,
+			wantArgs: []interface{}{"x", 1, 9},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := NewSQLBuilder("postgresql").Parameterized(true)
+			tt.fn(sb)
+			gotSQL, gotArgs := sb.BuildedSQLArgs()
+			if gotSQL != tt.wantSQL {
+				t.Errorf("SQLBuilder.BuildedSQLArgs() sql = %v, want %v", gotSQL, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("SQLBuilder.BuildedSQLArgs() args = %v, want %v", gotArgs, tt.wantArgs)
+			}
+			sb.Release()
+		})
+	}
+
+	t.Run("empty group", func(t *testing.T) {
+		_, _, err := NewSQLBuilder().Select("a").From("tblA").Wheres(Or()).BuildSelect()
+		if !errors.Is(err, ErrMissingCondition) {
+			t.Errorf("SQLBuilder.BuildSelect() error = %v, want %v", err, ErrMissingCondition)
+		}
+	})
+}
+
+func TestSQLBuilder_SetDriverType(t *testing.T) {
+	sb := NewSQLBuilder("mysql").Parameterized(true).QuoteIdentifiers(true)
+	sb.SelectExpr(Col("id"), As(Fn("COALESCE", Col("nick"), "guest"), "name")).
+		From("user").
+		Where("created", "<", Fn("NOW")).
+		Wheres(Or(On("flag", "=", true), On("flag", "is", nil))).
+		OrderByDesc("id")
+
+	tests := []struct {
+		name     string
+		driver   string
+		param    bool
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "mysql",
+			driver:   "mysql",
+			param:    true,
+			wantSQL:  "SELECT `id`,COALESCE(`nick`,?) AS `name` FROM `user` WHERE `created` < NOW() AND (`flag` = ? OR `flag` is NULL) ORDER BY `id` DESC",
+			wantArgs: []interface{}{"guest", true},
+		},
+		{
+			name:     "postgresql",
+			driver:   "postgresql",
+			param:    true,
+			wantSQL:  `SELECT "id",COALESCE("nick",$1) AS "name" FROM "user" WHERE "created" < NOW() AND ("flag" = $2 OR "flag" is NULL) ORDER BY "id" DESC`,
+			wantArgs: []interface{}{"guest", true},
+		},
+		{
+			name:     "mssql inline",
+			driver:   "mssql",
+			param:    false,
+			wantSQL:  `SELECT [id],COALESCE([nick],'guest') AS [name] FROM [user] WHERE [created] < NOW() AND ([flag] = 1 OR [flag] is NULL) ORDER BY [id] DESC`,
+			wantArgs: []interface{}{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb.SetDriverType(tt.driver)
+			gotSQL, gotArgs, err := sb.Parameterized(tt.param).BuildSelect()
+			if err != nil {
+				t.Fatalf("SQLBuilder.BuildSelect() error = %v", err)
+			}
+			if gotSQL != tt.wantSQL {
+				t.Errorf("SQLBuilder.BuildSelect() sql = %v, want %v", gotSQL, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("SQLBuilder.BuildSelect() args = %v, want %v", gotArgs, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestSQLBuilder_WhereIn(t *testing.T) {
+	tests := []struct {
+		name     string
+		param    bool
+		fn       func(sb *SQLBuilder)
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name: "case 1 : slices",
+			fn: func(sb *SQLBuilder) {
+				sb.Select("a").
+					From("tblA").
+					WhereIn("a", []int{1, 2, 3}).
+					WhereNotIn("b", []string{"x", "y'z"}).
+					Where("c", "in", [2]float64{1.5, 2}).
+					Where("d", "in", Var("(SELECT 1)")).
+					BuildSelectSQL()
+			},
+			wantSQL:  `SELECT a FROM tblA WHERE a IN (1,2,3) AND b NOT IN ('x','y\'z') AND c IN (1.5,2) AND d in (SELECT 1)`,
+			wantArgs: []interface{}{},
+		},
+		{
+			name:  "case 2 : placeholder",
+			param: true,
+			fn: func(sb *SQLBuilder) {
+				sb.Select("a").
+					From("tblA").
+					Where("z", "=", 0).
+					WhereIn("a", []interface{}{1, "b", nil}).
+					Wheres(Or(OnIn("c", []int64{7}), OnNotIn("d", []int{8}))).
+					BuildSelectSQL()
+			},
+			wantSQL:  `SELECT a FROM tblA WHERE z = ? AND a IN (?,?,NULL) AND (c IN (?) OR d NOT IN (?))`,
+			wantArgs: []interface{}{0, 1, "b", int64(7), 8},
+		},
+		{
+			name: "case 3 : empty",
+			fn: func(sb *SQLBuilder) {
+				sb.Select("a").
+					From("tblA").
+					WhereIn("a", []int{}).
+					WhereNotIn("b", nil).
+					BuildSelectSQL()
+			},
+			wantSQL:  `SELECT a FROM tblA WHERE 1=0 AND 1=1`,
+			wantArgs: []interface{}{},
+		},
+		{
+			name:  "case 4 : sub query",
+			param: true,
+			fn: func(sb *SQLBuilder) {
+				sub := NewSQLBuilder("mysql").Select("user_id").From("orders").Where("total", ">", 100)
+				sb.Select("name").
+					From("user").
+					Where("active", "=", 1).
+					WhereIn("id", sub).
+					WhereNotIn("id", NewSQLBuilder().Select("user_id").From("banned")).
+					BuildSelectSQL()
+			},
+			wantSQL:  `SELECT name FROM user WHERE active = ? AND id IN (SELECT user_id FROM orders WHERE total > ?) AND id NOT IN (SELECT user_id FROM banned)`,
+			wantArgs: []interface{}{1, 100},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := NewSQLBuilder("mysql").Parameterized(tt.param)
+			tt.fn(sb)
+			gotSQL, gotArgs := sb.BuildedSQLArgs()
+			if gotSQL != tt.wantSQL {
+				t.Errorf("SQLBuilder.BuildedSQLArgs() sql = %v, want %v", gotSQL, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("SQLBuilder.BuildedSQLArgs() args = %v, want %v", gotArgs, tt.wantArgs)
+			}
+			sb.Release()
+		})
+	}
+
+	invalids := map[string]func(sb *SQLBuilder) *SQLBuilder{
+		"WhereIn":      func(sb *SQLBuilder) *SQLBuilder { return sb.WhereIn("a", 1) },
+		"Where in":     func(sb *SQLBuilder) *SQLBuilder { return sb.Where("a", "in", 1) },
+		"OnIn":         func(sb *SQLBuilder) *SQLBuilder { return sb.Wheres(OnIn("a", 1)) },
+		"nil sub":      func(sb *SQLBuilder) *SQLBuilder { return sb.WhereIn("a", (*SQLBuilder)(nil)) },
+		"not in nil":   func(sb *SQLBuilder) *SQLBuilder { return sb.WhereNotIn("a", (*SQLBuilder)(nil)) },
+		"OnNotIn byte": func(sb *SQLBuilder) *SQLBuilder { return sb.Wheres(OnNotIn("a", []byte("x"))) },
+	}
+	for name, fn := range invalids {
+		t.Run("invalid "+name, func(t *testing.T) {
+			_, _, err := fn(NewSQLBuilder().Select("a").From("tblA")).BuildSelect()
+			if !errors.Is(err, ErrInvalidArgument) {
+				t.Errorf("SQLBuilder.BuildSelect() error = %v, want %v", err, ErrInvalidArgument)
+			}
+		})
+	}
+}
+
+func TestSQLBuilder_WhereHelpers(t *testing.T) {
+	tests := []struct {
+		name     string
+		driver   string
+		param    bool
+		fn       func(sb *SQLBuilder)
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name:   "case 1 : WHERE",
+			driver: "mysql",
+			fn: func(sb *SQLBuilder) {
+				sb.Select("a").
+					From("tblA").
+					WhereBetween("a", 1, 10).
+					WhereNotBetween("b", "x", "y").
+					WhereIsNull("c").
+					WhereIsNotNull("d").
+					WhereLike("e", "ab_%").
+					WhereContains("f", "50%_off!").
+					WhereStartsWith("g", "a_").
+					WhereEndsWith("h", "%z").
+					BuildSelectSQL()
+			},
+			wantSQL:  `SELECT a FROM tblA WHERE a BETWEEN 1 AND 10 AND b NOT BETWEEN 'x' AND 'y' AND c IS NULL AND d IS NOT NULL AND e LIKE 'ab_%' AND f LIKE '%50!%!_off!!%' ESCAPE '!' AND g LIKE 'a!_%' ESCAPE '!' AND h LIKE '%!%z' ESCAPE '!'`,
+			wantArgs: []interface{}{},
+		},
+		{
+			name:   "case 2 : JOIN ON, HAVING placeholder",
+			driver: "postgresql",
+			param:  true,
+			fn: func(sb *SQLBuilder) {
+				sb.Select("a.id").
+					From("tblA a").
+					JoinOns("tblB b", On("a.id", "=", Var("b.a_id")), OnIsNull("b.deleted_at")).
+					Wheres(Or(OnContains("a.name", "x%"), OnBetween("a.age", 18, 30))).
+					GroupBy("a.id").
+					Havings(OnNotBetween("count(*)", 2, 5)).
+					BuildSelectSQL()
+			},
+			wantSQL:  `SELECT a.id FROM tblA a JOIN tblB b ON a.id = b.a_id AND b.deleted_at IS NULL WHERE (a.name LIKE $1 ESCAPE '!' OR a.age BETWEEN $2 AND $3) GROUP BY a.id HAVING count(*) NOT BETWEEN $4 AND $5`,
+			wantArgs: []interface{}{"%x!%%", 18, 30, 2, 5},
+		},
+		{
+			name:   "case 3 : mssql char class",
+			driver: "mssql",
+			param:  true,
+			fn: func(sb *SQLBuilder) {
+				sb.Select("a").
+					From("tblA").
+					WhereContains("a", "[x]_").
+					WhereLike("b", "[ab]%").
+					BuildSelectSQL()
+			},
+			wantSQL:  `SELECT a FROM tblA WHERE a LIKE @p1 ESCAPE '!' AND b LIKE @p2`,
+			wantArgs: []interface{}{"%![x]!_%", "[ab]%"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := NewSQLBuilder(tt.driver).Parameterized(tt.param)
+			tt.fn(sb)
+			gotSQL, gotArgs := sb.BuildedSQLArgs()
+			if gotSQL != tt.wantSQL {
+				t.Errorf("SQLBuilder.BuildedSQLArgs() sql = %v, want %v", gotSQL, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("SQLBuilder.BuildedSQLArgs() args = %v, want %v", gotArgs, tt.wantArgs)
+			}
+			sb.Release()
+		})
+	}
+}
+
+func TestSQLBuilder_Subquery(t *testing.T) {
+	newSQLBuilder := func() *SQLBuilder {
+		sb := NewSQLBuilder("postgresql").Parameterized(true)
+		cnt := NewSQLBuilder().SelectExpr(Fn("COUNT", Col("*"))).From("orders o").Where("o.user_id", "=", Var("u.id")).Where("o.status", "=", "paid")
+		src := NewSQLBuilder().Select("id", "name").From("user").Where("active", "=", true)
+		lastLogin := NewSQLBuilder().SelectExpr(Col("user_id"), As(Fn("MAX", Col("at")), "at")).From("logins").Where("at", ">", "2020-01-01").GroupBy("user_id")
+		maxID := NewSQLBuilder().SelectExpr(Fn("MAX", Col("id"))).From("user").Where("name", "<>", "root")
+
+		sb.SelectExpr(Col("u.name"), Sub(cnt, "paid_cnt"), Col("l.at")).
+			FromExpr(Sub(src, "u")).
+			JoinExpr("left", Sub(lastLogin, "l"), On("l.user_id", "=", Var("u.id"))).
+			Where("u.name", "<>", "").
+			Where("u.id", "<", maxID)
+		return sb
+	}
+
+	t.Run("postgresql", func(t *testing.T) {
+		sql, args, err := newSQLBuilder().BuildSelect()
+		wantSQL := `SELECT u.name,(SELECT COUNT(*) FROM orders o WHERE o.user_id = u.id AND o.status = $1) paid_cnt,l.at FROM (SELECT id,name FROM user WHERE active = $2) u LEFT JOIN (SELECT user_id,MAX(at) AS at FROM logins WHERE at > $3 GROUP BY user_id) l ON l.user_id = u.id WHERE u.name <> $4 AND u.id < (SELECT MAX(id) FROM user WHERE name <> $5)`
+		wantArgs := []interface{}{"paid", true, "2020-01-01", "", "root"}
+		if err != nil || sql != wantSQL {
+			t.Errorf("SQLBuilder.BuildSelect() = %v, %v, want %v", sql, err, wantSQL)
+		}
+		if !reflect.DeepEqual(args, wantArgs) {
+			t.Errorf("SQLBuilder.BuildSelect() args = %v, want %v", args, wantArgs)
+		}
+	})
+
+	t.Run("mssql quote", func(t *testing.T) {
+		sb := newSQLBuilder().QuoteIdentifiers(true)
+		sb.SetDriverType("mssql")
+		sql, _, err := sb.BuildSelect()
+		wantSQL := `SELECT [u].[name],(SELECT COUNT(*) FROM [orders] [o] WHERE [o].[user_id] = u.id AND [o].[status] = @p1) [paid_cnt],[l].[at] FROM (SELECT [id],[name] FROM [user] WHERE [active] = @p2) [u] LEFT JOIN (SELECT [user_id],MAX([at]) AS [at] FROM [logins] WHERE [at] > @p3 GROUP BY [user_id]) [l] ON [l].[user_id] = u.id WHERE [u].[name] <> @p4 AND [u].[id] < (SELECT MAX([id]) FROM [user] WHERE [name] <> @p5)`
+		if err != nil || sql != wantSQL {
+			t.Errorf("SQLBuilder.BuildSelect() = %v, %v, want %v", sql, err, wantSQL)
+		}
+	})
+
+	t.Run("inner error", func(t *testing.T) {
+		sub := NewSQLBuilder().Select("id")
+		_, _, err := NewSQLBuilder().Select("a").From("tblA").WhereIn("a", sub).BuildSelect()
+		if !errors.Is(err, ErrMissingTable) {
+			t.Errorf("SQLBuilder.BuildSelect() error = %v, want %v", err, ErrMissingTable)
+		}
+	})
+}
+
+func TestSQLBuilder_WhereExists(t *testing.T) {
+	fn := func(sb *SQLBuilder) {
+		perm := NewSQLBuilder().Select("1").From("perm p").
+			Where("p.user_id", "=", Var("u.id")).
+			Where("p.name", "=", "admin")
+		banned := NewSQLBuilder().Select("1").From("banned b").
+			Where("b.user_id", "=", Var("u.id"))
+		sb.Select("u.id").
+			From("user u").
+			Where("u.active", "=", 1).
+			WhereExists(perm).
+			WhereNotExists(banned).
+			Wheres(Or(OnExists(NewSQLBuilder().Select("1").From("owner o").Where("o.user_id", "=", Var("u.id"))), OnIsNull("u.org"))).
+			BuildSelectSQL()
+	}
+	tests := []struct {
+		driver  string
+		wantSQL string
+	}{
+		{"mysql", `SELECT u.id FROM user u WHERE u.active = ? AND EXISTS (SELECT 1 FROM perm p WHERE p.user_id = u.id AND p.name = ?) AND NOT EXISTS (SELECT 1 FROM banned b WHERE b.user_id = u.id) AND (EXISTS (SELECT 1 FROM owner o WHERE o.user_id = u.id) OR u.org IS NULL)`},
+		{"SQLite", `SELECT u.id FROM user u WHERE u.active = ? AND EXISTS (SELECT 1 FROM perm p WHERE p.user_id = u.id AND p.name = ?) AND NOT EXISTS (SELECT 1 FROM banned b WHERE b.user_id = u.id) AND (EXISTS (SELECT 1 FROM owner o WHERE o.user_id = u.id) OR u.org IS NULL)`},
+		{"postgresql", `SELECT u.id FROM user u WHERE u.active = $1 AND EXISTS (SELECT 1 FROM perm p WHERE p.user_id = u.id AND p.name = $2) AND NOT EXISTS (SELECT 1 FROM banned b WHERE b.user_id = u.id) AND (EXISTS (SELECT 1 FROM owner o WHERE o.user_id = u.id) OR u.org IS NULL)`},
+		{"mssql", `SELECT u.id FROM user u WHERE u.active = @p1 AND EXISTS (SELECT 1 FROM perm p WHERE p.user_id = u.id AND p.name = @p2) AND NOT EXISTS (SELECT 1 FROM banned b WHERE b.user_id = u.id) AND (EXISTS (SELECT 1 FROM owner o WHERE o.user_id = u.id) OR u.org IS NULL)`},
+		{"oracle", `SELECT u.id FROM user u WHERE u.active = :1 AND EXISTS (SELECT 1 FROM perm p WHERE p.user_id = u.id AND p.name = :2) AND NOT EXISTS (SELECT 1 FROM banned b WHERE b.user_id = u.id) AND (EXISTS (SELECT 1 FROM owner o WHERE o.user_id = u.id) OR u.org IS NULL)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.driver, func(t *testing.T) {
+			sb := NewSQLBuilder(tt.driver).Parameterized(true)
+			fn(sb)
+			if gotSQL := sb.BuildedSQL(); gotSQL != tt.wantSQL {
+				t.Errorf("SQLBuilder.BuildedSQL() = %v, want %v", gotSQL, tt.wantSQL)
+			}
+			if gotArgs := sb.BuildedArgs(); !reflect.DeepEqual(gotArgs, []interface{}{1, "admin"}) {
+				t.Errorf("SQLBuilder.BuildedArgs() = %v, want %v", gotArgs, []interface{}{1, "admin"})
+			}
+			sb.Release()
+		})
+	}
+}
+
+func TestSQLBuilder_ClauseOrder(t *testing.T) {
+	tests := []struct {
+		driver  string
+		fn      func(sb *SQLBuilder)
+		wantSQL string
+		wantErr error
+	}{
+		{
+			driver: "mysql",
+			fn: func(sb *SQLBuilder) {
+				sb.Limit(10)
+			},
+			wantSQL: `SELECT DISTINCT a,count(b) FROM tblA JOIN tblB ON tblB.a = tblA.a WHERE a > 1 GROUP BY a HAVING count(b) > 2 ORDER BY a DESC LIMIT 10 FOR UPDATE`,
+		},
+		{
+			driver: "postgresql",
+			fn: func(sb *SQLBuilder) {
+				sb.Limit(10)
+			},
+			wantSQL: `SELECT DISTINCT a,count(b) FROM tblA JOIN tblB ON tblB.a = tblA.a WHERE a > 1 GROUP BY a HAVING count(b) > 2 ORDER BY a DESC LIMIT 10 FOR UPDATE`,
+		},
+		{
+			driver:  "oracle",
+			fn:      func(sb *SQLBuilder) {},
+			wantSQL: `SELECT DISTINCT a,count(b) FROM tblA JOIN tblB ON tblB.a = tblA.a WHERE a > 1 GROUP BY a HAVING count(b) > 2 ORDER BY a DESC FOR UPDATE`,
+		},
+		{
+			driver: "mssql",
+			fn: func(sb *SQLBuilder) {
+				sb.Top(10).ForUpdate(false)
+			},
+			wantSQL: `SELECT DISTINCT TOP 10 a,count(b) FROM tblA JOIN tblB ON tblB.a = tblA.a WHERE a > 1 GROUP BY a HAVING count(b) > 2 ORDER BY a DESC`,
+		},
+		{
+			driver: "SQLite",
+			fn: func(sb *SQLBuilder) {
+				sb.Limit(10)
+			},
+			wantErr: ErrUnsupported,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.driver, func(t *testing.T) {
+			sb := NewSQLBuilder(tt.driver)
+			sb.Select("a", "count(b)").
+				Distinct(true).
+				ForUpdate(true).
+				OrderByDesc("a").
+				GroupBy("a").
+				Having("count(b)", ">", 2).
+				Where("a", ">", 1).
+				JoinOn("tblB", "tblB.a", "=", Var("tblA.a")).
+				From("tblA")
+			tt.fn(sb)
+			sql, _, err := sb.BuildSelect()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("SQLBuilder.BuildSelect() error = %v, want %v", err, tt.wantErr)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("SQLBuilder.BuildSelect() = %v, want %v", sql, tt.wantSQL)
+			}
+			sb.Release()
+		})
+	}
+}
+
+func TestSQLBuilder_Paging(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		fn      func(sb *SQLBuilder)
+		wantSQL string
+		wantErr error
+	}{
+		{
+			name:    "mysql limit offset",
+			dialect: MysqlDialect{},
+			fn:      func(sb *SQLBuilder) { sb.Limit(10).Offset(20) },
+			wantSQL: `SELECT a FROM tblA ORDER BY a ASC LIMIT 10 OFFSET 20`,
+		},
+		{
+			name:    "mysql limit m,n",
+			dialect: MysqlDialect{},
+			fn:      func(sb *SQLBuilder) { sb.Limit(20, 10) },
+			wantSQL: `SELECT a FROM tblA ORDER BY a ASC LIMIT 10 OFFSET 20`,
+		},
+		{
+			name:    "postgresql page",
+			dialect: PostgresqlDialect{},
+			fn:      func(sb *SQLBuilder) { sb.Page(3, 10) },
+			wantSQL: `SELECT a FROM tblA ORDER BY a ASC LIMIT 10 OFFSET 20`,
+		},
+		{
+			name:    "SQLite first page",
+			dialect: SQLiteDialect{},
+			fn:      func(sb *SQLBuilder) { sb.Page(1, 10) },
+			wantSQL: `SELECT a FROM tblA ORDER BY a ASC LIMIT 10`,
+		},
+		{
+			name:    "SQLite offset without limit",
+			dialect: SQLiteDialect{},
+			fn:      func(sb *SQLBuilder) { sb.Offset(20) },
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:    "mssql limit is top",
+			dialect: MssqlDialect{},
+			fn:      func(sb *SQLBuilder) { sb.Limit(10) },
+			wantSQL: `SELECT TOP 10 a FROM tblA ORDER BY a ASC`,
+		},
+		{
+			name:    "mssql offset fetch",
+			dialect: MssqlDialect{},
+			fn:      func(sb *SQLBuilder) { sb.Page(3, 10) },
+			wantSQL: `SELECT a FROM tblA ORDER BY a ASC OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY`,
+		},
+		{
+			name:    "mssql legacy offset",
+			dialect: MssqlDialect{LegacyPaging: true},
+			fn:      func(sb *SQLBuilder) { sb.Page(3, 10) },
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:    "mssql top with limit",
+			dialect: MssqlDialect{},
+			fn:      func(sb *SQLBuilder) { sb.Top(1).Limit(10) },
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:    "oracle offset fetch",
+			dialect: OracleDialect{},
+			fn:      func(sb *SQLBuilder) { sb.Limit(10) },
+			wantSQL: `SELECT a FROM tblA ORDER BY a ASC OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY`,
+		},
+		{
+			name:    "oracle offset only",
+			dialect: OracleDialect{},
+			fn:      func(sb *SQLBuilder) { sb.Offset(5) },
+			wantSQL: `SELECT a FROM tblA ORDER BY a ASC OFFSET 5 ROWS`,
+		},
+		{
+			name:    "oracle legacy limit",
+			dialect: OracleDialect{LegacyPaging: true},
+			fn:      func(sb *SQLBuilder) { sb.Limit(10) },
+			wantSQL: `SELECT * FROM (SELECT a FROM tblA ORDER BY a ASC) WHERE ROWNUM <= 10`,
+		},
+		{
+			name:    "oracle legacy page",
+			dialect: OracleDialect{LegacyPaging: true},
+			fn:      func(sb *SQLBuilder) { sb.Page(3, 10) },
+			wantSQL: `SELECT * FROM (SELECT t_.*, ROWNUM rn_ FROM (SELECT a FROM tblA ORDER BY a ASC) t_ WHERE ROWNUM <= 30) WHERE rn_ > 20`,
+		},
+		{
+			name:    "invalid page",
+			dialect: MysqlDialect{},
+			fn:      func(sb *SQLBuilder) { sb.Page(0, 10) },
+			wantErr: ErrInvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := NewSQLBuilder()
+			sb.SetDialect(tt.dialect)
+			sb.Select("a").From("tblA").OrderBy("a")
+			tt.fn(sb)
+			sql, _, err := sb.BuildSelect()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("SQLBuilder.BuildSelect() error = %v, want %v", err, tt.wantErr)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("SQLBuilder.BuildSelect() = %v, want %v", sql, tt.wantSQL)
+			}
+			sb.Release()
+		})
+	}
+
+	sb := NewSQLBuilder("mssql")
+	sql, _, _ := sb.Select("a").From("tblA").Limit(5, 10).BuildSelect()
+	if want := `SELECT a FROM tblA ORDER BY (SELECT NULL) OFFSET 5 ROWS FETCH NEXT 10 ROWS ONLY`; sql != want {
+		t.Errorf("SQLBuilder.BuildSelect() = %v, want %v", sql, want)
+	}
+}
+
+func TestSQLBuilder_SeekAfter(t *testing.T) {
+	tests := []struct {
+		name     string
+		driver   string
+		fn       func(sb *SQLBuilder)
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  error
+	}{
+		{
+			name:   "row values",
+			driver: "postgresql",
+			fn: func(sb *SQLBuilder) {
+				sb.OrderByDesc("created_at", "id").SeekAfter([]string{"created_at", "id"}, []interface{}{"2019-01-01", 100})
+			},
+			wantSQL:  `SELECT a FROM tblA WHERE a = $1 AND (created_at,id) < ($2,$3) ORDER BY created_at DESC,id DESC LIMIT 10`,
+			wantArgs: []interface{}{1, "2019-01-01", 100},
+		},
+		{
+			name:   "or chain without row values",
+			driver: "mssql",
+			fn: func(sb *SQLBuilder) {
+				sb.OrderByAsc("created_at", "id").SeekAfter([]string{"created_at", "id"}, []interface{}{"2019-01-01", 100})
+			},
+			wantSQL:  `SELECT TOP 10 a FROM tblA WHERE a = @p1 AND (created_at > @p2 OR (created_at = @p3 AND id > @p4)) ORDER BY created_at ASC,id ASC`,
+			wantArgs: []interface{}{1, "2019-01-01", "2019-01-01", 100},
+		},
+		{
+			name:   "or chain with mixed directions",
+			driver: "mysql",
+			fn: func(sb *SQLBuilder) {
+				sb.OrderByDesc("score").OrderByAsc("id").SeekAfter([]string{"score", "id"}, []interface{}{90, 7})
+			},
+			wantSQL:  `SELECT a FROM tblA WHERE a = ? AND (score < ? OR (score = ? AND id > ?)) ORDER BY score DESC,id ASC LIMIT 10`,
+			wantArgs: []interface{}{1, 90, 90, 7},
+		},
+		{
+			name:   "after or condition",
+			driver: "postgresql",
+			fn: func(sb *SQLBuilder) {
+				sb.WhereOr("b", "=", 2).OrderBy("id").SeekAfter([]string{"id"}, []interface{}{7})
+			},
+			wantSQL:  `SELECT a FROM tblA WHERE (a = $1 OR b = $2) AND id > $3 ORDER BY id ASC LIMIT 10`,
+			wantArgs: []interface{}{1, 2, 7},
+		},
+		{
+			name:   "before or condition",
+			driver: "postgresql",
+			fn: func(sb *SQLBuilder) {
+				sb.OrderBy("id").SeekAfter([]string{"id"}, []interface{}{7}).WhereOr("b", "=", 2)
+			},
+			wantSQL:  `SELECT a FROM tblA WHERE (a = $1 OR b = $2) AND id > $3 ORDER BY id ASC LIMIT 10`,
+			wantArgs: []interface{}{1, 2, 7},
+		},
+		{
+			name:   "one column",
+			driver: "SQLite",
+			fn: func(sb *SQLBuilder) {
+				sb.SeekAfter([]string{"id"}, []interface{}{7}).OrderBy("id")
+			},
+			wantSQL:  `SELECT a FROM tblA WHERE a = ? AND id > ? ORDER BY id ASC LIMIT 10`,
+			wantArgs: []interface{}{1, 7},
+		},
+		{
+			name:   "not in order by",
+			driver: "mysql",
+			fn: func(sb *SQLBuilder) {
+				sb.OrderBy("id").SeekAfter([]string{"created_at", "id"}, []interface{}{"2019-01-01", 7})
+			},
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:   "values count",
+			driver: "mysql",
+			fn: func(sb *SQLBuilder) {
+				sb.OrderBy("id").SeekAfter([]string{"id"}, []interface{}{1, 2})
+			},
+			wantErr: ErrFieldValueCount,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := NewSQLBuilder(tt.driver).Parameterized(true)
+			sb.Select("a").From("tblA").Where("a", "=", 1).Limit(10)
+			tt.fn(sb)
+			sql, args, err := sb.BuildSelect()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("SQLBuilder.BuildSelect() error = %v, want %v", err, tt.wantErr)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("SQLBuilder.BuildSelect() = %v, want %v", sql, tt.wantSQL)
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("SQLBuilder.BuildSelect() args = %v, want %v", args, tt.wantArgs)
+			}
+			sb.Release()
+		})
+	}
+}
+
+func TestCursor(t *testing.T) {
+	cursor, err := EncodeCursor("2019-01-01", 100, 1.5, nil)
+	if err != nil {
+		t.Fatalf("EncodeCursor() error = %v", err)
+	}
+	vals, err := DecodeCursor(cursor)
+	if err != nil {
+		t.Fatalf("DecodeCursor() error = %v", err)
+	}
+	if want := []interface{}{"2019-01-01", int64(100), 1.5, nil}; !reflect.DeepEqual(vals, want) {
+		t.Errorf("DecodeCursor() = %v, want %v", vals, want)
+	}
+
+	if _, err = DecodeCursor("not a cursor!"); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("DecodeCursor() error = %v, want %v", err, ErrInvalidArgument)
+	}
+}
+
+func TestSQLBuilder_CountQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		fn       func(sb *SQLBuilder)
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "plain",
+			fn:       func(sb *SQLBuilder) {},
+			wantSQL:  `SELECT COUNT(*) FROM tblA JOIN tblB ON tblB.a = tblA.a WHERE a = ?`,
+			wantArgs: []interface{}{1},
+		},
+		{
+			name:     "distinct",
+			fn:       func(sb *SQLBuilder) { sb.Distinct(true) },
+			wantSQL:  `SELECT COUNT(*) FROM (SELECT DISTINCT a,b FROM tblA JOIN tblB ON tblB.a = tblA.a WHERE a = ?) t`,
+			wantArgs: []interface{}{1},
+		},
+		{
+			name:     "group by having",
+			fn:       func(sb *SQLBuilder) { sb.GroupBy("a", "b").Having("count(b)", ">", 2) },
+			wantSQL:  `SELECT COUNT(*) FROM (SELECT a,b FROM tblA JOIN tblB ON tblB.a = tblA.a WHERE a = ? GROUP BY a,b HAVING count(b) > ?) t`,
+			wantArgs: []interface{}{1, 2},
+		},
+		{
+			name:     "seek",
+			fn:       func(sb *SQLBuilder) { sb.WhereOr("b", "=", 3).SeekAfter([]string{"a"}, []interface{}{5}) },
+			wantSQL:  `SELECT COUNT(*) FROM tblA JOIN tblB ON tblB.a = tblA.a WHERE a = ? OR b = ?`,
+			wantArgs: []interface{}{1, 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := NewSQLBuilder().Parameterized(true)
+			sb.Select("a", "b").
+				From("tblA").
+				JoinOn("tblB", "tblB.a", "=", Var("tblA.a")).
+				Where("a", "=", 1).
+				OrderByDesc("a").
+				Limit(10, 20).
+				ForUpdate(true)
+			tt.fn(sb)
+			sql, args, err := sb.CountQuery().BuildSelect()
+			if err != nil {
+				t.Errorf("SQLBuilder.CountQuery() error = %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("SQLBuilder.CountQuery() = %v, want %v", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("SQLBuilder.CountQuery() args = %v, want %v", args, tt.wantArgs)
+			}
+
+			// the original builder is not changed
+			if !sb.IsHasOrders() || !sb.IsHasLimit() || !sb.IsForUpdate() {
+				t.Errorf("SQLBuilder.CountQuery() changed the original builder")
+			}
+			sb.Release()
+		})
+	}
+}
+
+func TestSQLBuilder_With(t *testing.T) {
+	tree := func(sb *SQLBuilder) *SQLBuilder {
+		return sb.WithRecursive("tree", []string{"id", "parent_id"},
+			NewSQLBuilder().Select("id", "parent_id").From("category").Where("id", "=", 1),
+			NewSQLBuilder().Select("c.id", "c.parent_id").From("category c").JoinOn("tree t", "t.id", "=", Var("c.parent_id")),
+		)
+	}
+	recent := func(sb *SQLBuilder) *SQLBuilder {
+		return sb.With("recent", NewSQLBuilder().Select("id").From("tblB").Where("d", ">", 7))
+	}
+
+	tests := []struct {
+		name     string
+		driver   string
+		fn       func(sb *SQLBuilder) (string, []interface{}, error)
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  error
+	}{
+		{
+			name:   "select",
+			driver: "mysql",
+			fn: func(sb *SQLBuilder) (string, []interface{}, error) {
+				return recent(sb).Select("a").From("tblA").Where("a", "=", 2).BuildSelect()
+			},
+			wantSQL:  `WITH recent AS (SELECT id FROM tblB WHERE d > ?) SELECT a FROM tblA WHERE a = ?`,
+			wantArgs: []interface{}{7, 2},
+		},
+		{
+			name:   "recursive postgresql",
+			driver: "postgresql",
+			fn: func(sb *SQLBuilder) (string, []interface{}, error) {
+				return recent(tree(sb)).Select("id").From("tree").Where("id", "<>", 3).BuildSelect()
+			},
+			wantSQL:  `WITH RECURSIVE tree(id,parent_id) AS (SELECT id,parent_id FROM category WHERE id = $1 UNION ALL SELECT c.id,c.parent_id FROM category c JOIN tree t ON t.id = c.parent_id),recent AS (SELECT id FROM tblB WHERE d > $2) SELECT id FROM tree WHERE id <> $3`,
+			wantArgs: []interface{}{1, 7, 3},
+		},
+		{
+			name:   "recursive mssql",
+			driver: "mssql",
+			fn: func(sb *SQLBuilder) (string, []interface{}, error) {
+				return tree(sb).Select("id").From("tree").BuildSelect()
+			},
+			wantSQL:  `WITH tree(id,parent_id) AS (SELECT id,parent_id FROM category WHERE id = @p1 UNION ALL SELECT c.id,c.parent_id FROM category c JOIN tree t ON t.id = c.parent_id) SELECT id FROM tree`,
+			wantArgs: []interface{}{1},
+		},
+		{
+			name:   "delete",
+			driver: "SQLite",
+			fn: func(sb *SQLBuilder) (string, []interface{}, error) {
+				return recent(sb).From("tblA").WhereIn("id", NewSQLBuilder().Select("id").From("recent")).BuildDelete()
+			},
+			wantSQL:  `WITH recent AS (SELECT id FROM tblB WHERE d > ?) DELETE FROM tblA WHERE id IN (SELECT id FROM recent)`,
+			wantArgs: []interface{}{7},
+		},
+		{
+			name:   "update",
+			driver: "mysql",
+			fn: func(sb *SQLBuilder) (string, []interface{}, error) {
+				return recent(sb).From("tblA").Set([]Set{{K: "a", V: 1}}).WhereIn("id", NewSQLBuilder().Select("id").From("recent")).BuildUpdate()
+			},
+			wantSQL:  `WITH recent AS (SELECT id FROM tblB WHERE d > ?) UPDATE tblA SET a=? WHERE id IN (SELECT id FROM recent)`,
+			wantArgs: []interface{}{7, 1},
+		},
+		{
+			name:   "insert",
+			driver: "postgresql",
+			fn: func(sb *SQLBuilder) (string, []interface{}, error) {
+				return recent(sb).Into("tblA").Fields("a").Values(1).BuildInsert()
+			},
+			wantSQL:  `WITH recent AS (SELECT id FROM tblB WHERE d > $1) INSERT INTO tblA (a) VALUES ($2)`,
+			wantArgs: []interface{}{7, 1},
+		},
+		{
+			name:   "insert mysql",
+			driver: "mysql",
+			fn: func(sb *SQLBuilder) (string, []interface{}, error) {
+				return recent(sb).Into("tblA").Fields("a").Values(1).BuildInsert()
+			},
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:   "update oracle",
+			driver: "oracle",
+			fn: func(sb *SQLBuilder) (string, []interface{}, error) {
+				return recent(sb).From("tblA").Set([]Set{{K: "a", V: 1}}).BuildUpdate()
+			},
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:   "recursive without columns",
+			driver: "mysql",
+			fn: func(sb *SQLBuilder) (string, []interface{}, error) {
+				return sb.WithRecursive("tree", nil, NewSQLBuilder(), NewSQLBuilder()).Select("id").From("tree").BuildSelect()
+			},
+			wantErr: ErrMissingFields,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := NewSQLBuilder(tt.driver).Parameterized(true)
+			sql, args, err := tt.fn(sb)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("SQLBuilder.With() error = %v, want %v", err, tt.wantErr)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("SQLBuilder.With() = %v, want %v", sql, tt.wantSQL)
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("SQLBuilder.With() args = %v, want %v", args, tt.wantArgs)
+			}
+			sb.Release()
+		})
+	}
+}
+
+func TestSQLBuilder_Compound(t *testing.T) {
+	archive := func() *SQLBuilder {
+		return NewSQLBuilder().Select("id").From("archive").Where("d", ">", 1)
+	}
+	latest := func() *SQLBuilder {
+		return NewSQLBuilder().Select("id").From("archive").OrderByDesc("id").Limit(5)
+	}
+
+	tests := []struct {
+		name     string
+		driver   string
+		fn       func(sb *SQLBuilder)
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  error
+	}{
+		{
+			name:     "union order limit",
+			driver:   "mysql",
+			fn:       func(sb *SQLBuilder) { sb.Union(archive()).OrderBy("id").Limit(10) },
+			wantSQL:  `SELECT id FROM live WHERE a = ? UNION SELECT id FROM archive WHERE d > ? ORDER BY id ASC LIMIT 10`,
+			wantArgs: []interface{}{1, 1},
+		},
+		{
+			name:     "union all with limited operand",
+			driver:   "postgresql",
+			fn:       func(sb *SQLBuilder) { sb.UnionAll(latest(), archive()) },
+			wantSQL:  `SELECT id FROM live WHERE a = $1 UNION ALL (SELECT id FROM archive ORDER BY id DESC LIMIT 5) UNION ALL SELECT id FROM archive WHERE d > $2`,
+			wantArgs: []interface{}{1, 1},
+		},
+		{
+			name:     "SQLite wrap operand",
+			driver:   "SQLite",
+			fn:       func(sb *SQLBuilder) { sb.Intersect(latest()) },
+			wantSQL:  `SELECT id FROM live WHERE a = ? INTERSECT SELECT * FROM (SELECT id FROM archive ORDER BY id DESC LIMIT 5)`,
+			wantArgs: []interface{}{1},
+		},
+		{
+			name:     "oracle minus",
+			driver:   "oracle",
+			fn:       func(sb *SQLBuilder) { sb.Except(archive()).OrderBy("id").Page(2, 10) },
+			wantSQL:  `SELECT id FROM live WHERE a = :1 MINUS SELECT id FROM archive WHERE d > :2 ORDER BY id ASC OFFSET 10 ROWS FETCH NEXT 10 ROWS ONLY`,
+			wantArgs: []interface{}{1, 1},
+		},
+		{
+			name:     "mssql limit",
+			driver:   "mssql",
+			fn:       func(sb *SQLBuilder) { sb.Except(archive()).OrderBy("id").Limit(10) },
+			wantSQL:  `SELECT id FROM live WHERE a = @p1 EXCEPT SELECT id FROM archive WHERE d > @p2 ORDER BY id ASC OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY`,
+			wantArgs: []interface{}{1, 1},
+		},
+		{
+			name:     "mssql limit without order",
+			driver:   "mssql",
+			fn:       func(sb *SQLBuilder) { sb.Union(archive()).Page(2, 10) },
+			wantSQL:  `SELECT * FROM (SELECT id FROM live WHERE a = @p1 UNION SELECT id FROM archive WHERE d > @p2) t ORDER BY (SELECT NULL) OFFSET 10 ROWS FETCH NEXT 10 ROWS ONLY`,
+			wantArgs: []interface{}{1, 1},
+		},
+		{
+			name:     "mssql top",
+			driver:   "mssql",
+			fn:       func(sb *SQLBuilder) { sb.Union(archive()).OrderBy("id").Top(10) },
+			wantSQL:  `SELECT TOP 10 * FROM (SELECT id FROM live WHERE a = @p1 UNION SELECT id FROM archive WHERE d > @p2) t ORDER BY id ASC`,
+			wantArgs: []interface{}{1, 1},
+		},
+		{
+			name:    "for update",
+			driver:  "mysql",
+			fn:      func(sb *SQLBuilder) { sb.Union(archive()).ForUpdate(true) },
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:    "missing query",
+			driver:  "mysql",
+			fn:      func(sb *SQLBuilder) { sb.Union() },
+			wantErr: ErrInvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := NewSQLBuilder(tt.driver).Parameterized(true)
+			sb.Select("id").From("live").Where("a", "=", 1)
+			tt.fn(sb)
+			sql, args, err := sb.BuildSelect()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("SQLBuilder.BuildSelect() error = %v, want %v", err, tt.wantErr)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("SQLBuilder.BuildSelect() = %v, want %v", sql, tt.wantSQL)
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("SQLBuilder.BuildSelect() args = %v, want %v", args, tt.wantArgs)
+			}
+			sb.Release()
+		})
+	}
+}
+
+func TestSQLBuilder_Window(t *testing.T) {
+	tests := []struct {
+		name    string
+		driver  string
+		fn      func(sb *SQLBuilder)
+		wantSQL string
+		wantErr error
+	}{
+		{
+			name:   "over spec",
+			driver: "mysql",
+			fn: func(sb *SQLBuilder) {
+				sb.SelectExpr(
+					As(Over(Fn("ROW_NUMBER"), NewWindowSpec().PartitionBy("dept").OrderByDesc("salary")), "rn"),
+					As(Over(Fn("SUM", Col("salary")), NewWindowSpec().OrderBy("id").Rows(UnboundedPreceding, CurrentRow)), "running"),
+					As(Over(Fn("LAG", Col("salary"), 1), NewWindowSpec().OrderBy("id")), "prev"),
+				)
+			},
+			wantSQL: `SELECT id,ROW_NUMBER() OVER (PARTITION BY dept ORDER BY salary DESC) AS rn,SUM(salary) OVER (ORDER BY id ASC ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) AS running,LAG(salary,1) OVER (ORDER BY id ASC) AS prev FROM emp`,
+		},
+		{
+			name:   "order by window function",
+			driver: "postgresql",
+			fn: func(sb *SQLBuilder) {
+				sb.OrderByDescExpr(Over(Fn("RANK"), NewWindowSpec().OrderBy("salary").Range(Preceding(1), "")))
+			},
+			wantSQL: `SELECT id FROM emp ORDER BY RANK() OVER (ORDER BY salary ASC RANGE 1 PRECEDING) DESC`,
+		},
+		{
+			name:   "named window",
+			driver: "postgresql",
+			fn: func(sb *SQLBuilder) {
+				sb.SelectExpr(As(OverWindow(Fn("AVG", Col("salary")), "w"), "avg")).
+					Window("w", NewWindowSpec().PartitionBy("dept").Rows(Preceding(2), Following(2))).
+					OrderBy("id")
+			},
+			wantSQL: `SELECT id,AVG(salary) OVER w AS avg FROM emp WINDOW w AS (PARTITION BY dept ROWS BETWEEN 2 PRECEDING AND 2 FOLLOWING) ORDER BY id ASC`,
+		},
+		{
+			name:   "named window inline",
+			driver: "mssql",
+			fn: func(sb *SQLBuilder) {
+				sb.SelectExpr(As(OverWindow(Fn("AVG", Col("salary")), "w"), "avg")).
+					Window("w", NewWindowSpec().PartitionBy("dept"))
+			},
+			wantSQL: `SELECT id,AVG(salary) OVER (PARTITION BY dept) AS avg FROM emp`,
+		},
+		{
+			name:   "undefined window",
+			driver: "mysql",
+			fn: func(sb *SQLBuilder) {
+				sb.SelectExpr(OverWindow(Fn("AVG", Col("salary")), "w"))
+			},
+			wantErr: ErrInvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := NewSQLBuilder(tt.driver)
+			sb.Select("id").From("emp")
+			tt.fn(sb)
+			sql, _, err := sb.BuildSelect()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("SQLBuilder.BuildSelect() error = %v, want %v", err, tt.wantErr)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("SQLBuilder.BuildSelect() = %v, want %v", sql, tt.wantSQL)
+			}
+			sb.Release()
+		})
+	}
+}
+
+func TestSQLBuilder_Case(t *testing.T) {
+	level := func() *CaseExpr {
+		return Case().
+			When(On("score", ">=", 90), "A").
+			When(And(On("score", ">=", 60), On("late", "=", false)), "B").
+			Else("C")
+	}
+
+	tests := []struct {
+		name     string
+		driver   string
+		param    bool
+		fn       func(sb *SQLBuilder) (string, []interface{}, error)
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  error
+	}{
+		{
+			name:   "select group order",
+			driver: "mysql",
+			fn: func(sb *SQLBuilder) (string, []interface{}, error) {
+				return sb.SelectExpr(As(level(), "level"), Fn("COUNT", Var("*"))).
+					From("tblA").
+					GroupByExpr(level()).
+					OrderByExpr(Case().When(On("name", "=", "it's"), 0).Else(1)).
+					BuildSelect()
+			},
+			wantSQL: `SELECT CASE WHEN score >= 90 THEN 'A' WHEN (score >= 60 AND late = false) THEN 'B' ELSE 'C' END AS level,COUNT(*) FROM tblA ` +
+				`GROUP BY CASE WHEN score >= 90 THEN 'A' WHEN (score >= 60 AND late = false) THEN 'B' ELSE 'C' END ` +
+				`ORDER BY CASE WHEN name = 'it\'s' THEN 0 ELSE 1 END ASC`,
+		},
+		{
+			name:   "update set",
+			driver: "postgresql",
+			param:  true,
+			fn: func(sb *SQLBuilder) (string, []interface{}, error) {
+				return sb.From("tblA").
+					Set([]Set{{K: "status", V: Case().When(On("qty", "=", 0), "sold out").Else(Col("status"))}}).
+					Where("id", "=", 7).
+					BuildUpdate()
+			},
+			wantSQL:  `UPDATE tblA SET status=CASE WHEN qty = $1 THEN $2 ELSE status END WHERE id = $3`,
+			wantArgs: []interface{}{0, "sold out", 7},
+		},
+		{
+			name:   "where operand",
+			driver: "SQLite",
+			param:  true,
+			fn: func(sb *SQLBuilder) (string, []interface{}, error) {
+				return sb.Select("id").From("tblA").WhereExpr(level(), "=", "A").BuildSelect()
+			},
+			wantSQL:  `SELECT id FROM tblA WHERE CASE WHEN score >= ? THEN ? WHEN (score >= ? AND late = ?) THEN ? ELSE ? END = ?`,
+			wantArgs: []interface{}{90, "A", 60, false, "B", "C", "A"},
+		},
+		{
+			name:   "without when",
+			driver: "mysql",
+			fn: func(sb *SQLBuilder) (string, []interface{}, error) {
+				return sb.SelectExpr(Case().Else(1)).From("tblA").BuildSelect()
+			},
+			wantErr: ErrMissingCondition,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := NewSQLBuilder(tt.driver).Parameterized(tt.param)
+			sql, args, err := tt.fn(sb)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("SQLBuilder.Build() error = %v, want %v", err, tt.wantErr)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("SQLBuilder.Build() = %v, want %v", sql, tt.wantSQL)
+			}
+			if tt.wantErr == nil && tt.param && !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("SQLBuilder.Build() args = %v, want %v", args, tt.wantArgs)
+			}
+			sb.Release()
+		})
+	}
+}
+
+func TestSQLBuilder_Upsert(t *testing.T) {
+	tests := []struct {
+		name     string
+		driver   string
+		bulk     bool
+		fn       func(sb *SQLBuilder)
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  error
+	}{
+		{
+			name:     "mysql",
+			driver:   "mysql",
+			fn:       func(sb *SQLBuilder) { sb.Upsert("id") },
+			wantSQL:  "INSERT INTO tblA (id,a,b) VALUES (?,?,?) ON DUPLICATE KEY UPDATE a=VALUES(a),b=VALUES(b)",
+			wantArgs: []interface{}{1, "x", 2},
+		},
+		{
+			name:     "mysql with set",
+			driver:   "mysql",
+			fn:       func(sb *SQLBuilder) { sb.Set([]Set{{K: "cnt", V: Var("cnt + 1")}}).Upsert("id").DoUpdateSet("a") },
+			wantSQL:  "INSERT INTO tblA (id,a,b) VALUES (?,?,?) ON DUPLICATE KEY UPDATE a=VALUES(a),cnt=cnt + 1",
+			wantArgs: []interface{}{1, "x", 2},
+		},
+		{
+			name:     "mysql do nothing",
+			driver:   "mysql",
+			fn:       func(sb *SQLBuilder) { sb.Upsert().DoNothing() },
+			wantSQL:  "INSERT INTO tblA (id,a,b) VALUES (?,?,?) ON DUPLICATE KEY UPDATE id=id",
+			wantArgs: []interface{}{1, "x", 2},
+		},
+		{
+			name:     "postgresql",
+			driver:   "postgresql",
+			bulk:     true,
+			fn:       func(sb *SQLBuilder) { sb.Upsert("id").DoUpdateSet("a").Set([]Set{{K: "c", V: 9}}) },
+			wantSQL:  "INSERT INTO tblA (id,a,b) VALUES ($1,$2,$3),($4,$5,$6) ON CONFLICT (id) DO UPDATE SET a=EXCLUDED.a,c=$7",
+			wantArgs: []interface{}{1, "x", 2, 3, "y", 4, 9},
+		},
+		{
+			name:     "SQLite do nothing",
+			driver:   "SQLite",
+			fn:       func(sb *SQLBuilder) { sb.Upsert().DoNothing() },
+			wantSQL:  "INSERT INTO tblA (id,a,b) VALUES (?,?,?) ON CONFLICT DO NOTHING",
+			wantArgs: []interface{}{1, "x", 2},
+		},
+		{
+			name:    "SQLite update without keys",
+			driver:  "SQLite",
+			fn:      func(sb *SQLBuilder) { sb.Upsert() },
+			wantErr: ErrMissingFields,
+		},
+		{
+			name:   "mssql",
+			driver: "mssql",
+			bulk:   true,
+			fn:     func(sb *SQLBuilder) { sb.Upsert("id") },
+			wantSQL: "MERGE INTO tblA t USING (SELECT @p1 AS id,@p2 AS a,@p3 AS b UNION ALL SELECT @p4 AS id,@p5 AS a,@p6 AS b) s ON (t.id = s.id)" +
+				" WHEN MATCHED THEN UPDATE SET t.a=s.a,t.b=s.b WHEN NOT MATCHED THEN INSERT (id,a,b) VALUES (s.id,s.a,s.b);",
+			wantArgs: []interface{}{1, "x", 2, 3, "y", 4},
+		},
+		{
+			name:   "oracle do nothing",
+			driver: "oracle",
+			fn:     func(sb *SQLBuilder) { sb.Upsert("id", "a").DoNothing() },
+			wantSQL: "MERGE INTO tblA t USING (SELECT :1 AS id,:2 AS a,:3 AS b FROM dual) s ON (t.id = s.id AND t.a = s.a)" +
+				" WHEN NOT MATCHED THEN INSERT (id,a,b) VALUES (s.id,s.a,s.b)",
+			wantArgs: []interface{}{1, "x", 2},
+		},
+		{
+			name:    "oracle key not in fields",
+			driver:  "oracle",
+			fn:      func(sb *SQLBuilder) { sb.Upsert("code") },
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:    "update column not in fields",
+			driver:  "postgresql",
+			fn:      func(sb *SQLBuilder) { sb.Upsert("id").DoUpdateSet("c") },
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:    "do nothing without upsert",
+			driver:  "postgresql",
+			fn:      func(sb *SQLBuilder) { sb.DoNothing() },
+			wantErr: ErrInvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := NewSQLBuilder(tt.driver).Parameterized(true)
+			sb.Into("tblA").Fields("id", "a", "b").Values(1, "x", 2)
+			if tt.bulk {
+				sb.Values(3, "y", 4)
+			}
+			tt.fn(sb)
+			build := sb.BuildInsert
+			if tt.bulk {
+				build = sb.BuildBulkInsert
+			}
+			sql, args, err := build()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("SQLBuilder.BuildInsert() error = %v, want %v", err, tt.wantErr)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("SQLBuilder.BuildInsert() = %v, want %v", sql, tt.wantSQL)
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("SQLBuilder.BuildInsert() args = %v, want %v", args, tt.wantArgs)
+			}
+			sb.Release()
+		})
+	}
+}
+
+func TestSQLBuilder_Merge(t *testing.T) {
+	tests := []struct {
+		name     string
+		driver   string
+		fn       func(sb *SQLBuilder)
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  error
+	}{
+		{
+			name:   "mssql table",
+			driver: "mssql",
+			fn: func(sb *SQLBuilder) {
+				sb.UsingTable("staging", "s").
+					MergeOn(On("t.id", "=", Col("s.id"))).
+					WhenMatchedDelete(On("s.deleted", "=", true)).
+					WhenMatchedUpdate([]Set{{K: "name", V: Col("s.name")}, {K: "ver", V: Var("t.ver + 1")}}).
+					WhenNotMatchedInsert([]string{"id", "name"}, []interface{}{Col("s.id"), Col("s.name")}).
+					WhenNotMatchedBySourceDelete(On("t.src", "=", "sync"), On("t.ver", ">", 1))
+			},
+			wantSQL: "MERGE INTO tblA t USING staging s ON (t.id = s.id)" +
+				" WHEN MATCHED AND s.deleted = @p1 THEN DELETE" +
+				" WHEN MATCHED THEN UPDATE SET name=s.name,ver=t.ver + 1" +
+				" WHEN NOT MATCHED THEN INSERT (id,name) VALUES (s.id,s.name)" +
+				" WHEN NOT MATCHED BY SOURCE AND (t.src = @p2 AND t.ver > @p3) THEN DELETE;",
+			wantArgs: []interface{}{true, "sync", 1},
+		},
+		{
+			name:   "mssql query",
+			driver: "mssql",
+			fn: func(sb *SQLBuilder) {
+				sb.UsingQuery(NewSQLBuilder().Select("id", "name").From("staging").Where("day", "=", 7), "s").
+					MergeOn(On("t.id", "=", Col("s.id"))).
+					WhenNotMatchedBySourceUpdate([]Set{{K: "active", V: false}})
+			},
+			wantSQL:  "MERGE INTO tblA t USING (SELECT id,name FROM staging WHERE day = @p1) s ON (t.id = s.id) WHEN NOT MATCHED BY SOURCE THEN UPDATE SET active=@p2;",
+			wantArgs: []interface{}{7, false},
+		},
+		{
+			name:   "oracle values",
+			driver: "oracle",
+			fn: func(sb *SQLBuilder) {
+				sb.UsingValues("s", []string{"id", "name"}, []interface{}{1, "a"}, []interface{}{2, "b"}).
+					MergeOn(On("t.id", "=", Col("s.id"))).
+					WhenNotMatchedInsert([]string{"id", "name"}, []interface{}{Col("s.id"), Col("s.name")}, On("s.name", "<>", "")).
+					WhenMatchedUpdate([]Set{{K: "name", V: Col("s.name")}}, On("t.name", "<>", Col("s.name"))).
+					WhenMatchedDelete(On("s.name", "=", "x"))
+			},
+			wantSQL: "MERGE INTO tblA t USING (SELECT :1 AS id,:2 AS name FROM dual UNION ALL SELECT :3 AS id,:4 AS name FROM dual) s ON (t.id = s.id)" +
+				" WHEN MATCHED THEN UPDATE SET name=s.name WHERE t.name <> s.name DELETE WHERE s.name = :5" +
+				" WHEN NOT MATCHED THEN INSERT (id,name) VALUES (s.id,s.name) WHERE s.name <> :6",
+			wantArgs: []interface{}{1, "a", 2, "b", "x", ""},
+		},
+		{
+			name:   "oracle by source",
+			driver: "oracle",
+			fn: func(sb *SQLBuilder) {
+				sb.UsingTable("staging", "s").MergeOn(On("t.id", "=", Col("s.id"))).WhenNotMatchedBySourceDelete()
+			},
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:   "oracle delete without update",
+			driver: "oracle",
+			fn: func(sb *SQLBuilder) {
+				sb.UsingTable("staging", "s").MergeOn(On("t.id", "=", Col("s.id"))).WhenMatchedDelete()
+			},
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:   "mysql",
+			driver: "mysql",
+			fn: func(sb *SQLBuilder) {
+				sb.UsingTable("staging", "s").MergeOn(On("t.id", "=", Col("s.id"))).WhenMatchedDelete()
+			},
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:   "without on",
+			driver: "mssql",
+			fn: func(sb *SQLBuilder) {
+				sb.UsingTable("staging", "s").WhenMatchedDelete()
+			},
+			wantErr: ErrMissingCondition,
+		},
+		{
+			name:   "values count",
+			driver: "mssql",
+			fn: func(sb *SQLBuilder) {
+				sb.UsingValues("s", []string{"id", "name"}, []interface{}{1})
+			},
+			wantErr: ErrFieldValueCount,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := NewSQLBuilder(tt.driver).Parameterized(true)
+			sb.Into("tblA t")
+			tt.fn(sb)
+			sql, args, err := sb.BuildMerge()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("SQLBuilder.BuildMerge() error = %v, want %v", err, tt.wantErr)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("SQLBuilder.BuildMerge() = %v, want %v", sql, tt.wantSQL)
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("SQLBuilder.BuildMerge() args = %v, want %v", args, tt.wantArgs)
+			}
+			sb.Release()
+		})
+	}
+}
+
+func TestSQLBuilder_Returning(t *testing.T) {
+	insert := func(sb *SQLBuilder) (string, []interface{}, error) {
+		return sb.Into("tblA").Fields("a", "b").Values(1, "x").Returning("id", "a").BuildInsert()
+	}
+	update := func(sb *SQLBuilder) (string, []interface{}, error) {
+		return sb.From("tblA").Set([]Set{{K: "a", V: 2}}).Where("id", "=", 7).Returning("id", "DELETED.a").BuildUpdate()
+	}
+	del := func(sb *SQLBuilder) (string, []interface{}, error) {
+		return sb.From("tblA").Where("id", "=", 7).Returning("id").BuildDelete()
+	}
+
+	tests := []struct {
+		name     string
+		driver   string
+		fn       func(sb *SQLBuilder) (string, []interface{}, error)
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  error
+	}{
+		{
+			name:     "postgresql insert",
+			driver:   "postgresql",
+			fn:       insert,
+			wantSQL:  `INSERT INTO tblA (a,b) VALUES ($1,$2) RETURNING id,a`,
+			wantArgs: []interface{}{1, "x"},
+		},
+		{
+			name:   "SQLite upsert",
+			driver: "SQLite",
+			fn: func(sb *SQLBuilder) (string, []interface{}, error) {
+				return sb.Into("tblA").Fields("id", "a").Values(1, 2).Upsert("id").Returning("id").BuildInsert()
+			},
+			wantSQL:  `INSERT INTO tblA (id,a) VALUES (?,?) ON CONFLICT (id) DO UPDATE SET a=EXCLUDED.a RETURNING id`,
+			wantArgs: []interface{}{1, 2},
+		},
+		{
+			name:     "mssql insert",
+			driver:   "mssql",
+			fn:       insert,
+			wantSQL:  `INSERT INTO tblA (a,b) OUTPUT INSERTED.id,INSERTED.a VALUES (@p1,@p2)`,
+			wantArgs: []interface{}{1, "x"},
+		},
+		{
+			name:     "mssql update",
+			driver:   "mssql",
+			fn:       update,
+			wantSQL:  `UPDATE tblA SET a=@p1 OUTPUT INSERTED.id,DELETED.a WHERE id = @p2`,
+			wantArgs: []interface{}{2, 7},
+		},
+		{
+			name:     "mssql delete",
+			driver:   "mssql",
+			fn:       del,
+			wantSQL:  `DELETE FROM tblA OUTPUT DELETED.id WHERE id = @p1`,
+			wantArgs: []interface{}{7},
+		},
+		{
+			name:     "oracle delete",
+			driver:   "oracle",
+			fn:       del,
+			wantSQL:  `DELETE FROM tblA WHERE id = :1 RETURNING id INTO :2`,
+			wantArgs: []interface{}{7},
+		},
+		{
+			name:     "oracle insert",
+			driver:   "oracle",
+			fn:       insert,
+			wantSQL:  `INSERT INTO tblA (a,b) VALUES (:1,:2) RETURNING id,a INTO :3,:4`,
+			wantArgs: []interface{}{1, "x"},
+		},
+		{
+			name:   "oracle bulk insert",
+			driver: "oracle",
+			fn: func(sb *SQLBuilder) (string, []interface{}, error) {
+				return sb.Into("tblA").Fields("a").Values(1).Values(2).Returning("id").BuildBulkInsert()
+			},
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:     "mariadb delete",
+			driver:   "mariadb",
+			fn:       del,
+			wantSQL:  "DELETE FROM tblA WHERE id = ? RETURNING id",
+			wantArgs: []interface{}{7},
+		},
+		{
+			name:    "mariadb update",
+			driver:  "mariadb",
+			fn:      update,
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:    "mysql",
+			driver:  "mysql",
+			fn:      insert,
+			wantErr: ErrUnsupported,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := NewSQLBuilder(tt.driver).Parameterized(true)
+			if got := sb.GetDriverType(); got != tt.driver {
+				t.Fatalf("SQLBuilder.GetDriverType() = %v, want %v", got, tt.driver)
+			}
+			sql, args, err := tt.fn(sb)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("SQLBuilder.Build() error = %v, want %v", err, tt.wantErr)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("SQLBuilder.Build() = %v, want %v", sql, tt.wantSQL)
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("SQLBuilder.Build() args = %v, want %v", args, tt.wantArgs)
+			}
+			sb.Release()
+		})
+	}
+}
+
+func TestSQLBuilder_FromSelect(t *testing.T) {
+	src := func() *SQLBuilder {
+		return NewSQLBuilder().Select("id", "a").From("tblB").Where("d", "=", 7)
+	}
+
+	tests := []struct {
+		name     string
+		driver   string
+		fn       func(sb *SQLBuilder)
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  error
+	}{
+		{
+			name:     "insert select",
+			driver:   "mysql",
+			fn:       func(sb *SQLBuilder) { sb.FromSelect(src()) },
+			wantSQL:  "INSERT INTO tblA (id,a) SELECT id,a FROM tblB WHERE d = ?",
+			wantArgs: []interface{}{7},
+		},
+		{
+			name:     "unknown columns count",
+			driver:   "mysql",
+			fn:       func(sb *SQLBuilder) { sb.FromSelect(NewSQLBuilder().Select("*").From("tblB")) },
+			wantSQL:  "INSERT INTO tblA (id,a) SELECT * FROM tblB",
+			wantArgs: []interface{}{},
+		},
+		{
+			name:   "mysql upsert with cte",
+			driver: "mysql",
+			fn: func(sb *SQLBuilder) {
+				sb.With("b", src()).FromSelect(NewSQLBuilder().Select("id", "a").From("b")).Upsert()
+			},
+			wantSQL:  "INSERT INTO tblA (id,a) WITH b AS (SELECT id,a FROM tblB WHERE d = ?) SELECT id,a FROM b ON DUPLICATE KEY UPDATE id=VALUES(id),a=VALUES(a)",
+			wantArgs: []interface{}{7},
+		},
+		{
+			name:   "SQLite upsert",
+			driver: "SQLite",
+			fn: func(sb *SQLBuilder) {
+				sb.FromSelect(NewSQLBuilder().Select("id", "a").From("tblB")).Upsert("id").Returning("id")
+			},
+			wantSQL:  "INSERT INTO tblA (id,a) SELECT id,a FROM tblB WHERE true ON CONFLICT (id) DO UPDATE SET a=EXCLUDED.a RETURNING id",
+			wantArgs: []interface{}{},
+		},
+		{
+			name:     "postgresql upsert",
+			driver:   "postgresql",
+			fn:       func(sb *SQLBuilder) { sb.FromSelect(src()).Upsert("id").DoNothing() },
+			wantSQL:  "INSERT INTO tblA (id,a) SELECT id,a FROM tblB WHERE d = $1 ON CONFLICT (id) DO NOTHING",
+			wantArgs: []interface{}{7},
+		},
+		{
+			name:     "mssql output",
+			driver:   "mssql",
+			fn:       func(sb *SQLBuilder) { sb.FromSelect(src()).Returning("id") },
+			wantSQL:  "INSERT INTO tblA (id,a) OUTPUT INSERTED.id SELECT id,a FROM tblB WHERE d = @p1",
+			wantArgs: []interface{}{7},
+		},
+		{
+			name:   "oracle upsert",
+			driver: "oracle",
+			fn:     func(sb *SQLBuilder) { sb.FromSelect(src()).Upsert("id") },
+			wantSQL: "MERGE INTO tblA t USING (SELECT id,a FROM tblB WHERE d = :1) s ON (t.id = s.id)" +
+				" WHEN MATCHED THEN UPDATE SET t.a=s.a WHEN NOT MATCHED THEN INSERT (id,a) VALUES (s.id,s.a)",
+			wantArgs: []interface{}{7},
+		},
+		{
+			name:    "oracle returning",
+			driver:  "oracle",
+			fn:      func(sb *SQLBuilder) { sb.FromSelect(src()).Returning("id") },
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:    "columns count",
+			driver:  "mysql",
+			fn:      func(sb *SQLBuilder) { sb.FromSelect(NewSQLBuilder().Select("id").From("tblB")) },
+			wantErr: ErrFieldValueCount,
+		},
+		{
+			name:    "values and select",
+			driver:  "mysql",
+			fn:      func(sb *SQLBuilder) { sb.Values(1, 2).FromSelect(src()) },
+			wantErr: ErrInvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := NewSQLBuilder(tt.driver).Parameterized(true)
+			sb.Into("tblA").Fields("id", "a")
+			tt.fn(sb)
+			sql, args, err := sb.BuildInsert()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("SQLBuilder.BuildInsert() error = %v, want %v", err, tt.wantErr)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("SQLBuilder.BuildInsert() = %v, want %v", sql, tt.wantSQL)
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("SQLBuilder.BuildInsert() args = %v, want %v", args, tt.wantArgs)
+			}
+			sb.Release()
+		})
+	}
+}
+
+func TestSQLBuilder_MultiTable(t *testing.T) {
+	update := func(sb *SQLBuilder) (string, []interface{}, error) {
+		return sb.From("tblA a").InnerJoinOn("tblB b", "b.id", "=", Var("a.id")).
+			Set([]Set{{K: "v", V: 1}}).Where("b.d", "=", 7).BuildUpdate()
+	}
+	del := func(sb *SQLBuilder) (string, []interface{}, error) {
+		return sb.From("tblA a").InnerJoinOn("tblB b", "b.id", "=", Var("a.id")).Where("b.d", "=", 7).BuildDelete()
+	}
+
+	tests := []struct {
+		name     string
+		driver   string
+		fn       func(sb *SQLBuilder) (string, []interface{}, error)
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  error
+	}{
+		{
+			name:     "mysql update",
+			driver:   "mysql",
+			fn:       update,
+			wantSQL:  "UPDATE tblA a INNER JOIN tblB b ON b.id = a.id SET v=? WHERE b.d = ?",
+			wantArgs: []interface{}{1, 7},
+		},
+		{
+			name:   "mysql update with on value",
+			driver: "mysql",
+			fn: func(sb *SQLBuilder) (string, []interface{}, error) {
+				return sb.From("a").JoinOn("b", "b.x", "=", 5).Set([]Set{{K: "a.y", V: 7}}).Where("a.z", "=", 9).BuildUpdate()
+			},
+			wantSQL:  "UPDATE a JOIN b ON b.x = ? SET a.y=? WHERE a.z = ?",
+			wantArgs: []interface{}{5, 7, 9},
+		},
+		{
+			name:   "mysql update with sub query join",
+			driver: "mysql",
+			fn: func(sb *SQLBuilder) (string, []interface{}, error) {
+				return sb.From("a").
+					JoinExpr("inner", Sub(NewSQLBuilder().Select("id").From("c").Where("k", "=", 2), "s"), On("s.id", "=", Var("a.id"))).
+					Set([]Set{{K: "a.y", V: 1}}).BuildUpdate()
+			},
+			wantSQL:  "UPDATE a INNER JOIN (SELECT id FROM c WHERE k = ?) s ON s.id = a.id SET a.y=?",
+			wantArgs: []interface{}{2, 1},
+		},
+		{
+			name:     "postgresql update",
+			driver:   "postgresql",
+			fn:       update,
+			wantSQL:  "UPDATE tblA a SET v=$1 FROM tblB b WHERE b.id = a.id AND b.d = $2",
+			wantArgs: []interface{}{1, 7},
+		},
+		{
+			name:     "mssql update",
+			driver:   "mssql",
+			fn:       update,
+			wantSQL:  "UPDATE a SET v=@p1 FROM tblA a INNER JOIN tblB b ON b.id = a.id WHERE b.d = @p2",
+			wantArgs: []interface{}{1, 7},
+		},
+		{
+			name:     "SQLite update",
+			driver:   "SQLite",
+			fn:       update,
+			wantSQL:  "UPDATE tblA a SET v=? FROM tblB b WHERE b.id = a.id AND b.d = ?",
+			wantArgs: []interface{}{1, 7},
+		},
+		{
+			name:    "oracle update",
+			driver:  "oracle",
+			fn:      update,
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:     "mysql delete",
+			driver:   "mysql",
+			fn:       del,
+			wantSQL:  "DELETE a FROM tblA a INNER JOIN tblB b ON b.id = a.id WHERE b.d = ?",
+			wantArgs: []interface{}{7},
+		},
+		{
+			name:     "postgresql delete",
+			driver:   "postgresql",
+			fn:       del,
+			wantSQL:  "DELETE FROM tblA a USING tblB b WHERE b.id = a.id AND b.d = $1",
+			wantArgs: []interface{}{7},
+		},
+		{
+			name:     "mssql delete",
+			driver:   "mssql",
+			fn:       del,
+			wantSQL:  "DELETE a FROM tblA a INNER JOIN tblB b ON b.id = a.id WHERE b.d = @p1",
+			wantArgs: []interface{}{7},
+		},
+		{
+			name:    "SQLite delete",
+			driver:  "SQLite",
+			fn:      del,
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:   "mysql delete from tables",
+			driver: "mysql",
+			fn: func(sb *SQLBuilder) (string, []interface{}, error) {
+				return sb.From("tblA", "tblB").Where("tblA.id", "=", Var("tblB.id")).BuildDelete()
+			},
+			wantSQL:  "DELETE tblA FROM tblA,tblB WHERE tblA.id = tblB.id",
+			wantArgs: []interface{}{},
+		},
+		{
+			name:   "postgresql groups of conditions",
+			driver: "postgresql",
+			fn: func(sb *SQLBuilder) (string, []interface{}, error) {
+				return sb.From("tblA a").
+					JoinOns("tblB b", On("b.id", "=", Var("a.id")), On("b.d", "=", 7)).
+					Set([]Set{{K: "v", V: 1}}).Where("a.x", "=", 2).WhereOr("a.y", "=", 3).
+					Returning("a.id").BuildUpdate()
+			},
+			wantSQL:  "UPDATE tblA a SET v=$1 FROM tblB b WHERE (b.id = a.id AND b.d = $2) AND (a.x = $3 OR a.y = $4) RETURNING a.id",
+			wantArgs: []interface{}{1, 7, 2, 3},
+		},
+		{
+			name:   "mssql output",
+			driver: "mssql",
+			fn: func(sb *SQLBuilder) (string, []interface{}, error) {
+				return sb.From("tblA a").InnerJoinOn("tblB b", "b.id", "=", Var("a.id")).Returning("id").BuildDelete()
+			},
+			wantSQL:  "DELETE a OUTPUT DELETED.id FROM tblA a INNER JOIN tblB b ON b.id = a.id",
+			wantArgs: []interface{}{},
+		},
+		{
+			name:   "mariadb delete returning",
+			driver: "mariadb",
+			fn: func(sb *SQLBuilder) (string, []interface{}, error) {
+				return sb.From("tblA a").InnerJoinOn("tblB b", "b.id", "=", Var("a.id")).Returning("id").BuildDelete()
+			},
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:   "postgresql left join",
+			driver: "postgresql",
+			fn: func(sb *SQLBuilder) (string, []interface{}, error) {
+				return sb.From("tblA a").LeftJoinOn("tblB b", "b.id", "=", Var("a.id")).BuildDelete()
+			},
+			wantErr: ErrUnsupported,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := NewSQLBuilder(tt.driver).Parameterized(true)
+			sql, args, err := tt.fn(sb)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("SQLBuilder.Build() error = %v, want %v", err, tt.wantErr)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("SQLBuilder.Build() = %v, want %v", sql, tt.wantSQL)
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("SQLBuilder.Build() args = %v, want %v", args, tt.wantArgs)
+			}
+			sb.Release()
+		})
+	}
+}
@@ -2,34 +2,63 @@
 
 package sqlbuilder
 
+import (
+	"log"
+)
+
 // SQLBuilder is the main struct type
 type SQLBuilder struct {
-	driverType string
+	dialect Dialect
 
 	// default database and table
 	dbName     string
 	tbName     string
 	buildedStr string
 
+	// for parameterized query
+	parameterized bool
+	quoteIdent    bool
+	buildedArgs   []interface{}
+
 	// for select , delete
-	distinct bool
-	selects  []string
-	froms    []string
-	joins    []string
-	wheres   []string
-	orders   []string
-	groups   []string
-	havings  string
-	limit    string
-	top      string
+	ctes      []cte
+	distinct  bool
+	selects   []Expr
+	froms     []Expr
+	joins     []join
+	wheres    []SubCond
+	orders    []order
+	groups    []Expr
+	havings   []SubCond
+	windows   []window
+	compounds []compound
+	limit     int
+	offset    int
+	top       int
+	forUpdate bool
 
 	// for insert
-	into   string
-	fields []string
-	values [][]interface{}
+	into       string
+	fields     []string
+	values     [][]interface{}
+	fromSelect *SQLBuilder
 
 	// for update
 	sets []Set
+
+	// for insert or update
+	upsert *upsert
+	merge  *merge
+
+	// for insert, update, delete and merge
+	returnings []string
+
+	// the errors that recorded by the chain functions
+	errs      []error
+	buildErrs []error
+	errPolicy ErrorPolicy
+	logger    *log.Logger
+	errLogFn  func(err error)
 }
 
 // SQLVar can that you sql internal function via NewSQLVar()
@@ -43,10 +72,42 @@ type Set struct {
 	V interface{}
 }
 
-// SubCond struct for join condition
+// SubCond struct for join, where and having condition
+// it can be a group of conditions that rendered with parentheses
 type SubCond struct {
 	c bool // true is and else or
-	s string
-	o string
-	v interface{}
+	e Expr // the condition expression, ex : `s o v`
+
+	// for a group of conditions, ex : And(), Or(), Not()
+	op   string // `AND` or `OR`, it is a group when not empty
+	not  bool
+	subs []SubCond
+}
+
+// join is a join clause, the on conditions are rendered at build time
+type join struct {
+	p  string // join type prefix, ex : `INNER `
+	t  Expr
+	on []SubCond
+}
+
+// order is a order by column
+type order struct {
+	e    Expr
+	desc bool
+}
+
+// buildCtx keeps the state while building one SQL string
+// the settings are from the builder that start the build,
+// so the sub queries are rendered as same as the outer query
+type buildCtx struct {
+	dialect       Dialect
+	parameterized bool
+	quoteIdent    bool
+
+	args []interface{}
+	errs []error
+
+	// the named windows of the select that is rendering
+	windows []window
 }
@@ -0,0 +1,198 @@
+// Author :		Eric<eehsiao@gmail.com>
+
+package sqlbuilder
+
+import (
+	"strings"
+)
+
+// upsert is the insert or update of conflict rows
+type upsert struct {
+	keys    []string // the conflict columns
+	updates []string // the columns that update by the inserted values
+	nothing bool
+}
+
+// Upsert set the insert as a insert or update, the keys are the conflict columns
+// then DoUpdateSet() or DoNothing() for the conflict rows, default is DoUpdateSet()
+// it is rendered by the UpsertStyle() of dialect :
+// `ON DUPLICATE KEY UPDATE` for mysql, the keys are not need,
+// `ON CONFLICT (keys) DO UPDATE SET` for postgresql and SQLite,
+// `MERGE INTO ... USING ... ON keys` for mssql and oracle
+// ex :
+// ```
+// Into("tblA").Fields("id", "a", "b").Values(1, 2, 3).Upsert("id").DoUpdateSet("a").BuildInsert()
+// ```
+func (sb *SQLBuilder) Upsert(keys ...string) *SQLBuilder {
+	sb.upsert = &upsert{keys: keys}
+
+	return sb
+}
+
+// DoUpdateSet update the cols by the inserted values for the conflict rows,
+// default is all the fields except the conflict columns
+// the Set() are also updated with their values, ex : `Set([]Set{{"cnt", Var("cnt + 1")}})`
+func (sb *SQLBuilder) DoUpdateSet(cols ...string) *SQLBuilder {
+	if !sb.IsHasUpsert() {
+		sb.addError(ErrInvalidArgument, "must be set Upsert() first")
+		return sb
+	}
+
+	sb.upsert.updates, sb.upsert.nothing = cols, false
+
+	return sb
+}
+
+// DoNothing keep the conflict rows as it is
+func (sb *SQLBuilder) DoNothing() *SQLBuilder {
+	if !sb.IsHasUpsert() {
+		sb.addError(ErrInvalidArgument, "must be set Upsert() first")
+		return sb
+	}
+
+	sb.upsert.updates, sb.upsert.nothing = nil, true
+
+	return sb
+}
+
+// IsHasUpsert is internal function
+func (sb *SQLBuilder) IsHasUpsert() bool {
+	return sb.upsert != nil
+}
+
+// upsertCols return the columns that update by the inserted values
+func (sb *SQLBuilder) upsertCols(ctx *buildCtx) ([]string, bool) {
+	if sb.upsert.nothing {
+		return nil, true
+	}
+
+	if len(sb.upsert.updates) > 0 {
+		for _, c := range sb.upsert.updates {
+			if !inStrings(sb.fields, c) {
+				ctx.addError(ErrInvalidArgument, "update column "+c+" is not in fields")
+				return nil, false
+			}
+		}
+		return sb.upsert.updates, true
+	}
+
+	cols := make([]string, 0, len(sb.fields))
+	for _, f := range sb.fields {
+		if !inStrings(sb.upsert.keys, f) {
+			cols = append(cols, f)
+		}
+	}
+	if len(cols) == 0 && !sb.IsHasSets() {
+		ctx.addError(ErrMissingFields, "no column to update for upsert")
+		return nil, false
+	}
+
+	return cols, true
+}
+
+// upsertClause render the clause after `INSERT ... VALUES ...`
+func (sb *SQLBuilder) upsertClause(ctx *buildCtx) (string, bool) {
+	cols, ok := sb.upsertCols(ctx)
+	if !ok {
+		return "", false
+	}
+
+	sets := make([]string, 0, len(cols)+len(sb.sets))
+	switch ctx.dialect.UpsertStyle() {
+	case UpsertOnDuplicateKey:
+		if sb.upsert.nothing {
+			// update a column to itself, `INSERT IGNORE` will ignore the other errors too
+			f := ctx.ident(sb.fields[0])
+			return " ON DUPLICATE KEY UPDATE " + f + "=" + f, true
+		}
+		for _, c := range cols {
+			sets = append(sets, ctx.ident(c)+"=VALUES("+ctx.ident(c)+")")
+		}
+		sql := " ON DUPLICATE KEY UPDATE " + strings.Join(append(sets, ctx.sets(sb.sets, "")...), ",")
+		return sql, true
+	case UpsertOnConflict:
+		sql := " ON CONFLICT"
+		if len(sb.upsert.keys) > 0 {
+			sql += " (" + ctx.idents(sb.upsert.keys) + ")"
+		}
+		if sb.upsert.nothing {
+			return sql + " DO NOTHING", true
+		}
+		if len(sb.upsert.keys) == 0 {
+			ctx.addError(ErrMissingFields, "conflict columns are required for do update by "+ctx.dialect.Name())
+			return "", false
+		}
+		for _, c := range cols {
+			sets = append(sets, ctx.ident(c)+"=EXCLUDED."+ctx.ident(c))
+		}
+		return sql + " DO UPDATE SET " + strings.Join(append(sets, ctx.sets(sb.sets, "")...), ","), true
+	}
+
+	ctx.addError(ErrUnsupported, "upsert not support by "+ctx.dialect.Name())
+	return "", false
+}
+
+// buildMergeUpsert render the upsert as `MERGE`, the rows or the select are the source
+// the `WITH` is before the select when with is true
+func (sb *SQLBuilder) buildMergeUpsert(ctx *buildCtx, rows [][]interface{}, with bool) string {
+	if len(sb.upsert.keys) == 0 {
+		ctx.addError(ErrMissingFields, "conflict columns are required by "+ctx.dialect.Name())
+		return ""
+	}
+	for _, k := range sb.upsert.keys {
+		if !inStrings(sb.fields, k) {
+			ctx.addError(ErrInvalidArgument, "conflict column "+k+" is not in fields")
+			return ""
+		}
+	}
+	cols, ok := sb.upsertCols(ctx)
+	if !ok {
+		return ""
+	}
+
+	ons := make([]string, 0, len(sb.upsert.keys))
+	for _, k := range sb.upsert.keys {
+		ons = append(ons, "t."+ctx.ident(k)+" = s."+ctx.ident(k))
+	}
+
+	src := ""
+	if sb.IsHasFromSelect() {
+		if src, ok = sb.insertSelect(ctx, with); !ok {
+			return ""
+		}
+	} else {
+		src = ctx.selectRows(sb.fields, rows)
+	}
+	sql := "MERGE INTO " + sb.intoTable(ctx) + " t USING (" + src + ") s ON (" + strings.Join(ons, " AND ") + ")"
+
+	if !sb.upsert.nothing {
+		sets := make([]string, 0, len(cols)+len(sb.sets))
+		for _, c := range cols {
+			sets = append(sets, "t."+ctx.ident(c)+"=s."+ctx.ident(c))
+		}
+		sql += " WHEN MATCHED THEN UPDATE SET " + strings.Join(append(sets, ctx.sets(sb.sets, "t.")...), ",")
+	}
+
+	vals := make([]string, 0, len(sb.fields))
+	for _, f := range sb.fields {
+		vals = append(vals, "s."+ctx.ident(f))
+	}
+	sql += " WHEN NOT MATCHED THEN INSERT (" + ctx.idents(sb.fields) + ") VALUES (" + strings.Join(vals, ",") + ")"
+
+	tail, ok := sb.mergeTail(ctx)
+	if !ok {
+		return ""
+	}
+
+	return sql + tail
+}
+
+func inStrings(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,194 @@
+// Author :		Eric<eehsiao@gmail.com>
+
+package sqlbuilder
+
+import (
+	"strconv"
+	"strings"
+)
+
+// FrameBound is a bound of window frame, ex : `UNBOUNDED PRECEDING`
+type FrameBound string
+
+// the frame bounds
+const (
+	UnboundedPreceding FrameBound = "UNBOUNDED PRECEDING"
+	CurrentRow         FrameBound = "CURRENT ROW"
+	UnboundedFollowing FrameBound = "UNBOUNDED FOLLOWING"
+)
+
+// Preceding return the `n PRECEDING` frame bound
+func Preceding(n int) FrameBound {
+	return FrameBound(strconv.Itoa(n) + " PRECEDING")
+}
+
+// Following return the `n FOLLOWING` frame bound
+func Following(n int) FrameBound {
+	return FrameBound(strconv.Itoa(n) + " FOLLOWING")
+}
+
+// WindowSpec is the window of `OVER (...)` or `WINDOW w AS (...)`
+type WindowSpec struct {
+	partitions []Expr
+	orders     []order
+	frame      string
+	start      FrameBound
+	end        FrameBound
+}
+
+// window is a named window of builder
+type window struct {
+	name string
+	spec *WindowSpec
+}
+
+// over is a window function, ex : `ROW_NUMBER() OVER (ORDER BY id ASC)`
+type over struct {
+	fn   Expr
+	spec *WindowSpec
+	name string
+}
+
+// NewWindowSpec can create a window spec
+// ex :
+// ```
+// NewWindowSpec().PartitionBy("dept").OrderByDesc("salary").Rows(UnboundedPreceding, CurrentRow)
+// ```
+func NewWindowSpec() *WindowSpec {
+	return &WindowSpec{}
+}
+
+// PartitionBy set the `PARTITION BY` columns
+func (w *WindowSpec) PartitionBy(cols ...string) *WindowSpec {
+	for _, c := range cols {
+		w.partitions = append(w.partitions, Col(c))
+	}
+
+	return w
+}
+
+// OrderBy set the `ORDER BY` columns, same as OrderByAsc
+func (w *WindowSpec) OrderBy(cols ...string) *WindowSpec {
+	return w.OrderByAsc(cols...)
+}
+
+// OrderByAsc set the `ORDER BY` columns with `ASC`
+func (w *WindowSpec) OrderByAsc(cols ...string) *WindowSpec {
+	for _, c := range cols {
+		w.orders = append(w.orders, order{e: Col(c)})
+	}
+
+	return w
+}
+
+// OrderByDesc set the `ORDER BY` columns with `DESC`
+func (w *WindowSpec) OrderByDesc(cols ...string) *WindowSpec {
+	for _, c := range cols {
+		w.orders = append(w.orders, order{e: Col(c), desc: true})
+	}
+
+	return w
+}
+
+// Rows set the `ROWS BETWEEN start AND end` frame, it is `ROWS start` when end is empty
+func (w *WindowSpec) Rows(start FrameBound, end FrameBound) *WindowSpec {
+	w.frame, w.start, w.end = "ROWS", start, end
+
+	return w
+}
+
+// Range set the `RANGE BETWEEN start AND end` frame, it is `RANGE start` when end is empty
+func (w *WindowSpec) Range(start FrameBound, end FrameBound) *WindowSpec {
+	w.frame, w.start, w.end = "RANGE", start, end
+
+	return w
+}
+
+func (w *WindowSpec) build(ctx *buildCtx) string {
+	strs := make([]string, 0, 3)
+	if len(w.partitions) > 0 {
+		strs = append(strs, "PARTITION BY "+ctx.exprs(w.partitions))
+	}
+	if len(w.orders) > 0 {
+		strs = append(strs, "ORDER BY "+ctx.orders(w.orders))
+	}
+	if w.frame != "" {
+		if w.end == "" {
+			strs = append(strs, w.frame+" "+string(w.start))
+		} else {
+			strs = append(strs, w.frame+" BETWEEN "+string(w.start)+" AND "+string(w.end))
+		}
+	}
+
+	return "(" + strings.Join(strs, " ") + ")"
+}
+
+// Over return a window function expression, it can use in SelectExpr() or OrderByExpr()
+// ex :
+// ```
+// SelectExpr(As(Over(Fn("ROW_NUMBER"), NewWindowSpec().OrderBy("id")), "rn"))
+// ```
+func Over(fn Expr, w *WindowSpec) Expr {
+	if w == nil {
+		w = NewWindowSpec()
+	}
+
+	return over{fn: fn, spec: w}
+}
+
+// OverWindow return a window function expression with a named window of Window()
+// the window spec is inline for the dialect that not support the `WINDOW` clause
+func OverWindow(fn Expr, name string) Expr {
+	return over{fn: fn, name: name}
+}
+
+func (e over) build(ctx *buildCtx) string {
+	if e.spec != nil {
+		return e.fn.build(ctx) + " OVER " + e.spec.build(ctx)
+	}
+
+	for _, w := range ctx.windows {
+		if w.name != e.name {
+			continue
+		}
+		if ctx.dialect.NamedWindow() {
+			return e.fn.build(ctx) + " OVER " + ctx.ident(e.name)
+		}
+		return e.fn.build(ctx) + " OVER " + w.spec.build(ctx)
+	}
+	ctx.addError(ErrInvalidArgument, "window "+e.name+" is not defined")
+
+	return ""
+}
+
+// Window add a named window, it is rendered as `WINDOW name AS (...)` after the having
+// the dialect that not support the `WINDOW` clause, the spec is inline in OverWindow()
+func (sb *SQLBuilder) Window(name string, w *WindowSpec) *SQLBuilder {
+	if name == "" || w == nil {
+		sb.addError(ErrInvalidArgument, "must have name and spec for window")
+		return sb
+	}
+
+	sb.windows = append(sb.windows, window{name: name, spec: w})
+
+	return sb
+}
+
+// IsHasWindows is internal function
+func (sb *SQLBuilder) IsHasWindows() bool {
+	return len(sb.windows) > 0
+}
+
+// windowClause render the `WINDOW` clause, empty if the dialect not support it
+func (sb *SQLBuilder) windowClause(ctx *buildCtx) string {
+	if !sb.IsHasWindows() || !ctx.dialect.NamedWindow() {
+		return ""
+	}
+
+	strs := make([]string, 0, len(sb.windows))
+	for _, w := range sb.windows {
+		strs = append(strs, ctx.ident(w.name)+" AS "+w.spec.build(ctx))
+	}
+
+	return " WINDOW " + strings.Join(strs, ",")
+}